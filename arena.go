@@ -8,11 +8,32 @@ import (
 // Arena is a pool of RLP values.
 type Arena struct {
 	c cache
+
+	// lc are the elements of the raw arrays, which reference the
+	// encoding of the array instead of owning their content
+	lc cache
+
+	// decoders are the decoders of the raw arrays for each ParserOptions
+	decoders []*lazyDecoder
 }
 
 // Reset resets the values allocated in the arena.
 func (a *Arena) Reset() {
 	a.c.reset()
+	a.lc.reset()
+}
+
+// decoder returns the decoder of the raw arrays of the arena that enforces opts
+func (a *Arena) decoder(opts ParserOptions) *lazyDecoder {
+	for _, d := range a.decoders {
+		if d.opts == opts {
+			d.c = &a.lc
+			return d
+		}
+	}
+	d := &lazyDecoder{c: &a.lc, opts: opts}
+	a.decoders = append(a.decoders, d)
+	return d
 }
 
 // NewString returns a new string value.
@@ -31,9 +52,9 @@ func (a *Arena) NewBigInt(b *big.Int) *Value {
 // NewCopyBytes returns a bytes value that copies the input.
 func (a *Arena) NewCopyBytes(b []byte) *Value {
 	v := a.c.getValue()
-	v.t = TypeBytes
-	v.b = append(v.b[:0], b...)
-	v.l = uint64(len(b))
+	v.setBytes(b)
+	v.lazy = nil
+	v.strict = false
 	return v
 }
 
@@ -52,9 +73,40 @@ func (a *Arena) NewUint(i uint64) *Value {
 	binary.BigEndian.PutUint64(a.c.buf[:], i)
 
 	v := a.c.getValue()
-	v.t = TypeBytes
-	v.b = append(v.b[:0], a.c.buf[8-intSize:]...)
-	v.l = intSize
+	v.setBytes(a.c.buf[8-intSize:])
+	v.lazy = nil
+	v.strict = false
+	return v
+}
+
+// NewRaw returns a value with an existing RLP encoding. enc must be exactly
+// one valid RLP value and it is copied as it is when the value is marshaled.
+func (a *Arena) NewRaw(enc []byte) (*Value, error) {
+	if err := Validate(enc); err != nil {
+		return nil, err
+	}
+	return a.newRaw(enc), nil
+}
+
+// newRaw returns a value with the encoding enc, which is not validated.
+// The elements of arrays are decoded on first access.
+func (a *Arena) newRaw(enc []byte) *Value {
+	v := a.c.getValue()
+	v.enc = append(v.enc[:0], enc...)
+	v.i = 0
+	v.parent = nil
+	v.lazy = nil
+	v.strict = false
+
+	t, hdr, size, _ := readHeader(v.enc)
+	v.t = t
+	v.l = size
+	v.hdr = uint8(hdr)
+	if t == TypeArray {
+		v.a = v.a[:0]
+		v.depth = 0
+		v.lazy = a.decoder(ParserOptions{})
+	}
 	return v
 }
 
@@ -64,6 +116,12 @@ func (a *Arena) NewArray() *Value {
 	v.t = TypeArray
 	v.a = v.a[:0]
 	v.l = 0
+	// keep the buffer to reuse it for bytes after a reset
+	v.enc = v.enc[:0]
+	v.hdr = 0
+	v.lazy = nil
+	v.strict = false
+	v.parent = nil
 	return v
 }
 
@@ -85,12 +143,14 @@ func (a *Arena) NewFalse() *Value {
 	return valueFalse
 }
 
-// NewNullArray returns a null array value.
+// NewNullArray returns a null array value. It is an empty array,
+// the same as the parsed 0xC0.
 func (a *Arena) NewNullArray() *Value {
-	return valueArrayNull
+	return a.NewArray()
 }
 
-// NewNull returns a new null value.
+// NewNull returns a new null value. It is an empty bytes value,
+// the same as the parsed 0x80.
 func (a *Arena) NewNull() *Value {
 	return valueNull
 }
@@ -195,3 +195,161 @@ func validate(v *Value, expected string) error {
 	}
 	return nil
 }
+
+func TestArenaEncoding(t *testing.T) {
+	a := &Arena{}
+
+	v := a.NewArray()
+	v.Set(a.NewString("dog"))
+	if !bytes.Equal(v.Encoding(), v.MarshalTo(nil)) {
+		t.Fatal("bad encoding")
+	}
+
+	// the cached encoding is updated after the value changes
+	v.Set(a.NewUint(1000))
+	if !bytes.Equal(v.Encoding(), v.MarshalTo(nil)) {
+		t.Fatal("bad encoding")
+	}
+
+	for _, vv := range []*Value{a.NewNull(), a.NewNullArray(), a.NewTrue(), a.NewFalse()} {
+		if !bytes.Equal(vv.Encoding(), vv.MarshalTo(nil)) {
+			t.Fatal("bad encoding")
+		}
+	}
+
+	// hash an arena value
+	p := &Parser{}
+	pv, err := p.Parse(v.MarshalTo(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(p.Hash(nil, v), p.Hash(nil, pv)) {
+		t.Fatal("bad hash")
+	}
+}
+
+func TestArenaNewRaw(t *testing.T) {
+	a := &Arena{}
+
+	tx := a.NewArray()
+	tx.Set(a.NewUint(1))
+	tx.Set(a.NewString("data"))
+	enc := tx.MarshalTo(nil)
+
+	raw, err := a.NewRaw(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(raw.MarshalTo(nil), enc) {
+		t.Fatal("bad raw")
+	}
+
+	// embed the raw value in a new list
+	v := a.NewArray()
+	v.Set(raw)
+	v.Set(a.NewUint(2))
+
+	expected := a.NewArray()
+	expected.Set(tx)
+	expected.Set(a.NewUint(2))
+	if !bytes.Equal(v.MarshalTo(nil), expected.MarshalTo(nil)) {
+		t.Fatal("bad list")
+	}
+
+	// the elements of the raw value can be accessed
+	if num, err := raw.Get(0).GetUint64(); err != nil || num != 1 {
+		t.Fatalf("bad num %d %v", num, err)
+	}
+
+	// raw bytes value
+	raw, err = a.NewRaw([]byte{0x83, 0x64, 0x6f, 0x67})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := raw.GetString(); err != nil || s != "dog" {
+		t.Fatalf("bad string %s %v", s, err)
+	}
+
+	// invalid encodings
+	for _, enc := range [][]byte{{}, {0x01, 0x01}, {0x83, 0x64}, {0x81, 0x01}} {
+		if _, err := a.NewRaw(enc); err == nil {
+			t.Fatalf("%x: it should fail", enc)
+		}
+	}
+}
+
+func TestArenaNewRawReset(t *testing.T) {
+	b := &Arena{}
+	tx := b.NewArray()
+	tx.Set(b.NewUint(1))
+	inner := b.NewArray()
+	inner.Set(b.NewString("data"))
+	tx.Set(inner)
+	enc, innerEnc := tx.MarshalTo(nil), inner.MarshalTo(nil)
+
+	a := &Arena{}
+	run := func() {
+		a.Reset()
+		raw, err := a.NewRaw(enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		elem := raw.Get(1)
+		if !bytes.Equal(elem.Get(0).Encoding(), []byte{0x84, 'd', 'a', 't', 'a'}) {
+			t.Fatal("bad string")
+		}
+		// the new values do not overwrite the elements of the raw array
+		a.NewUint(300)
+		if !bytes.Equal(elem.Encoding(), innerEnc) {
+			t.Fatal("bad raw")
+		}
+	}
+
+	// the elements of the raw arrays are reused after a reset
+	run()
+	if allocs := testing.AllocsPerRun(100, run); allocs != 0 {
+		t.Fatalf("expected no allocations but found %f", allocs)
+	}
+}
+
+func TestArenaNullMatchesParsed(t *testing.T) {
+	a := &Arena{}
+	p := &Parser{}
+
+	for _, v := range []*Value{a.NewNull(), a.NewNullArray(), a.NewFalse(), a.NewUint(0)} {
+		pv, err := p.Parse(v.MarshalTo(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.Type() != pv.Type() {
+			t.Fatalf("expected %s but found %s", pv.Type(), v.Type())
+		}
+		if v.Len() != pv.Len() {
+			t.Fatalf("expected len %d but found %d", pv.Len(), v.Len())
+		}
+
+		if v.Type() == TypeBytes {
+			b, err := v.GetBytes(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(b) != 0 {
+				t.Fatal("expected empty bytes")
+			}
+			if num, err := v.GetUint64(); err != nil || num != 0 {
+				t.Fatalf("bad num %d %v", num, err)
+			}
+			if res, err := v.GetBool(); err != nil || res {
+				t.Fatalf("bad bool %v %v", res, err)
+			}
+		} else {
+			elems, err := v.GetElems()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(elems) != 0 {
+				t.Fatal("expected no elements")
+			}
+		}
+	}
+}
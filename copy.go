@@ -0,0 +1,33 @@
+package fastrlp
+
+// CopyTo returns a deep copy of v allocated in the arena a.
+//
+// Parsed values reference the buffer and the cache of the Parser and are
+// invalid once the Parser is reused. The copy does not reference any
+// memory of the Parser. Lazy arrays are copied with their encoding and
+// their elements are decoded on first access with the same ParserOptions.
+func (v *Value) CopyTo(a *Arena) *Value {
+	if v.lazy != nil {
+		vv := a.newRaw(v.Encoding())
+		vv.lazy = a.decoder(v.lazy.opts)
+		vv.depth = v.depth
+		// keep the offset so that the errors are the same as in v
+		vv.i = v.i
+		return vv
+	}
+	if v.t == TypeBytes {
+		vv := a.NewCopyBytes(v.content())
+		vv.strict = v.strict
+		return vv
+	}
+	vv := a.NewArray()
+	for _, elem := range v.a {
+		vv.Set(elem.CopyTo(a))
+	}
+	return vv
+}
+
+// Clone returns a deep copy of v allocated in the heap.
+func (v *Value) Clone() *Value {
+	return v.CopyTo(&Arena{})
+}
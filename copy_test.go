@@ -0,0 +1,124 @@
+package fastrlp
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValueCopyTo(t *testing.T) {
+	p := &Parser{}
+	a := &Arena{}
+
+	for i := 0; i < 100; i++ {
+		buf := generateRandom().MarshalTo(nil)
+
+		v, err := p.Parse(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v0 := v.CopyTo(a)
+		v1 := v.Clone()
+
+		// reuse the parser with a different input
+		if _, err := p.Parse(generateRandom().MarshalTo(nil)); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(v0.MarshalTo(nil), buf) {
+			t.Fatal("bad copy")
+		}
+		if !bytes.Equal(v1.MarshalTo(nil), buf) {
+			t.Fatal("bad clone")
+		}
+		a.Reset()
+	}
+}
+
+func TestValueCopyLazy(t *testing.T) {
+	buf := generateRandom().MarshalTo(nil)
+
+	p := &Parser{}
+	v, err := p.ParseLazy(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v = v.Clone()
+
+	if _, err := p.Parse([]byte{0x01}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(v.MarshalTo(nil), buf) {
+		t.Fatal("bad clone")
+	}
+}
+
+func TestValueCopyLazyOptions(t *testing.T) {
+	// the copies of lazy arrays enforce the options of the parser
+	p := NewParser(ParserOptions{Strict: true})
+	v, err := p.ParseLazy([]byte{0xC4, 0xC3, 0x82, 0x00, 0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, vv := range []*Value{v.Clone(), v.Clone().Clone()} {
+		if _, err := vv.Get(0).Get(0).GetUint64(); !errors.Is(err, ErrCanonInt) {
+			t.Fatalf("expected canonical error but found %v", err)
+		}
+	}
+
+	// and they keep their depth
+	p = NewParser(ParserOptions{MaxDepth: 2})
+	v, err = p.ParseLazy([]byte{0xC3, 0xC2, 0xC1, 0xC0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone := v.Get(0).Clone()
+
+	walk := func(path []int, v *Value) WalkAction {
+		return WalkContinue
+	}
+	err = v.Walk(walk)
+	if !errors.Is(err, ErrMaxDepth) {
+		t.Fatalf("expected depth error but found %v", err)
+	}
+	if cerr := clone.Walk(walk); !reflect.DeepEqual(cerr, err) {
+		t.Fatalf("expected '%v' but found '%v'", err, cerr)
+	}
+}
+
+func TestValueCopyNull(t *testing.T) {
+	a := &Arena{}
+	v := a.NewArray()
+	v.Set(a.NewNull())
+	v.Set(a.NewNullArray())
+	v.Set(a.NewTrue())
+
+	if !bytes.Equal(v.Clone().MarshalTo(nil), v.MarshalTo(nil)) {
+		t.Fatal("bad clone")
+	}
+}
+
+func TestValueCopyReuseArena(t *testing.T) {
+	p := NewParser(ParserOptions{Strict: true})
+	v, err := p.Parse([]byte{0x82, 0x00, 0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the values built in a reused arena do not keep the strict flag of a copy
+	a := &Arena{}
+	for _, build := range []func() *Value{
+		func() *Value { return a.NewBytes([]byte{0x0, 0x1}) },
+		func() *Value { return a.newRaw([]byte{0x82, 0x00, 0x01}) },
+	} {
+		if vv := v.CopyTo(a); vv.checkCanonInt() != ErrCanonInt {
+			t.Fatal("the copy should be strict")
+		}
+		a.Reset()
+		if _, err := build().GetUint64(); err != nil {
+			t.Fatal(err)
+		}
+		a.Reset()
+	}
+}
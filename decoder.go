@@ -0,0 +1,173 @@
+package fastrlp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// Decoder reads and decodes RLP values from an input stream.
+// Only the bytes of the value being decoded are kept in memory.
+type Decoder struct {
+	r *bufio.Reader
+	p Parser
+
+	// maxItemSize is the maximum size of a value in the stream
+	maxItemSize uint64
+
+	// offset is the position of the next value in the stream
+	offset uint64
+
+	// err is the first error, which is returned by all the later calls
+	err error
+}
+
+// DecoderOption is an option for the Decoder.
+type DecoderOption func(d *Decoder)
+
+// WithMaxItemSize sets the maximum size of a value in the stream.
+func WithMaxItemSize(size uint64) DecoderOption {
+	return func(d *Decoder) {
+		d.maxItemSize = size
+	}
+}
+
+// WithParserOptions sets the limits enforced while decoding each value.
+func WithParserOptions(opts ParserOptions) DecoderOption {
+	return func(d *Decoder) {
+		d.p.SetOptions(opts)
+	}
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader, opts ...DecoderOption) *Decoder {
+	d := &Decoder{
+		r: bufio.NewReader(r),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Next reads the next value from the stream. It returns io.EOF
+// if there are no more values. Once it fails, it returns the
+// same error in all the later calls.
+//
+// The value is valid until the next call to Next or Decode.
+func (d *Decoder) Next() (*Value, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	v, err := d.next()
+	if err != nil {
+		d.err = err
+	}
+	return v, err
+}
+
+func (d *Decoder) next() (*Value, error) {
+	// read the header
+	cur, err := d.r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	d.p.buf = append(d.p.buf[:0], cur)
+
+	hdr := headerLen(cur)
+	if hdr > 1 {
+		if err := d.read(hdr - 1); err != nil {
+			return nil, err
+		}
+	}
+	_, _, size, err := readSize(d.p.buf)
+	if err != nil {
+		return nil, &ParseError{Offset: d.offset, Kind: err}
+	}
+
+	if limit := d.itemSizeLimit(); limit != 0 && (hdr > limit || size > limit-hdr) {
+		err := newLimitError(ErrMaxInputSize, limit)
+		err.Offset = d.offset
+		return nil, err
+	}
+	if err := checkValueSize(hdr, size); err != nil {
+		return nil, &ParseError{Offset: d.offset, Kind: err}
+	}
+
+	// read the content, single byte values do not have a header
+	if hdr != 0 {
+		if err := d.read(size); err != nil {
+			return nil, err
+		}
+	}
+
+	total := hdr + size
+	v, err := d.p.parseBuf()
+	if err != nil {
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			perr.Offset += d.offset
+		}
+		return nil, err
+	}
+	d.offset += total
+	return v, nil
+}
+
+// itemSizeLimit returns the maximum size of a value in the stream
+func (d *Decoder) itemSizeLimit() uint64 {
+	limit := d.maxItemSize
+	if max := d.p.opts.MaxInputSize; max != 0 && (limit == 0 || max < limit) {
+		limit = max
+	}
+	return limit
+}
+
+// read appends size bytes from the stream to the parser buffer
+func (d *Decoder) read(size uint64) error {
+	var err error
+	if d.p.buf, err = appendRead(d.r, d.p.buf, size); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return &ParseError{Offset: d.offset, Kind: ErrTruncated, Err: io.ErrUnexpectedEOF}
+		}
+		return err
+	}
+	return nil
+}
+
+// readChunkSize is the maximum number of bytes that a buffer grows before
+// they are read. The size in a header is not trusted to allocate the buffer
+// since the bytes may never arrive.
+const readChunkSize = 32 * 1024
+
+// appendRead appends size bytes from r to buf. The buffer grows as the
+// bytes are read.
+func appendRead(r io.Reader, buf []byte, size uint64) ([]byte, error) {
+	for size > 0 {
+		n := size
+		if n > readChunkSize {
+			n = readChunkSize
+		}
+		start := len(buf)
+		buf = append(buf, make([]byte, n)...)
+		if _, err := io.ReadFull(r, buf[start:]); err != nil {
+			return buf[:start], err
+		}
+		size -= n
+	}
+	return buf, nil
+}
+
+// Decode reads the next value from the stream and unmarshals it into m.
+func (d *Decoder) Decode(m Unmarshaler) error {
+	v, err := d.Next()
+	if err != nil {
+		return err
+	}
+	return m.UnmarshalRLPWith(v)
+}
+
+// Offset returns the position in the stream of the next value.
+func (d *Decoder) Offset() uint64 {
+	return d.offset
+}
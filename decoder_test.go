@@ -0,0 +1,132 @@
+package fastrlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"runtime"
+	"testing"
+	"testing/iotest"
+)
+
+func TestDecoder(t *testing.T) {
+	var vals [][]byte
+	var buf []byte
+	for i := 0; i < 100; i++ {
+		val := generateRandom().MarshalTo(nil)
+		vals = append(vals, val)
+		buf = append(buf, val...)
+	}
+
+	// read one byte at a time to split the values across reads
+	d := NewDecoder(iotest.OneByteReader(bytes.NewReader(buf)))
+
+	var offset uint64
+	for _, val := range vals {
+		v, err := d.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(v.MarshalTo(nil), val) {
+			t.Fatal("bad value")
+		}
+		offset += uint64(len(val))
+		if d.Offset() != offset {
+			t.Fatalf("expected offset %d but found %d", offset, d.Offset())
+		}
+	}
+	if _, err := d.Next(); err != io.EOF {
+		t.Fatalf("expected EOF but found %v", err)
+	}
+}
+
+func TestDecoderDecode(t *testing.T) {
+	var buf []byte
+	for i := 0; i < 10; i++ {
+		obj := &Simple{Data1: []byte{0x1, 0x2}, Data3: uint64(i)}
+		data, err := obj.MarshalRLPTo(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf = append(buf, data...)
+	}
+
+	d := NewDecoder(bytes.NewReader(buf))
+	for i := 0; i < 10; i++ {
+		obj := &Simple{}
+		if err := d.Decode(obj); err != nil {
+			t.Fatal(err)
+		}
+		if obj.Data3 != uint64(i) {
+			t.Fatalf("expected %d but found %d", i, obj.Data3)
+		}
+	}
+	if err := d.Decode(&Simple{}); err != io.EOF {
+		t.Fatalf("expected EOF but found %v", err)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	// truncated value
+	d := NewDecoder(bytes.NewReader([]byte{0x01, 0x83, 0x64}))
+	if _, err := d.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, err := d.Next()
+	if !errors.Is(err, ErrTruncated) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected truncated error but found %v", err)
+	}
+
+	// invalid value
+	d = NewDecoder(bytes.NewReader([]byte{0x01, 0xC2, 0x81, 0x01}))
+	if _, err := d.Next(); err != nil {
+		t.Fatal(err)
+	}
+	_, err = d.Next()
+	var perr *ParseError
+	if !errors.As(err, &perr) || !errors.Is(err, ErrNonCanonicalSize) {
+		t.Fatalf("expected non canonical error but found %v", err)
+	}
+	if perr.Offset != 2 {
+		t.Fatalf("expected offset 2 but found %d", perr.Offset)
+	}
+	// the error is returned again instead of decoding the rest of the value
+	if _, err2 := d.Next(); err2 != err {
+		t.Fatalf("expected the same error but found %v", err2)
+	}
+
+	// value too large, the content is never read
+	d = NewDecoder(bytes.NewReader([]byte{0xBB, 0xFF, 0xFF, 0xFF, 0xFF}), WithMaxItemSize(1024))
+	if _, err := d.Next(); !errors.Is(err, ErrMaxInputSize) {
+		t.Fatalf("expected limit error but found %v", err)
+	}
+
+	// size that overflows the total length of the value
+	overflow := []byte{0xBF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	d = NewDecoder(bytes.NewReader(overflow), WithMaxItemSize(1024))
+	if _, err := d.Next(); !errors.Is(err, ErrMaxInputSize) {
+		t.Fatalf("expected limit error but found %v", err)
+	}
+	d = NewDecoder(bytes.NewReader(overflow))
+	if _, err := d.Next(); !errors.Is(err, ErrSizeOverflow) {
+		t.Fatalf("expected overflow error but found %v", err)
+	}
+}
+
+func TestDecoderForgedSize(t *testing.T) {
+	// the header claims 2GB but only a few bytes arrive
+	input := []byte{0xBB, 0x80, 0x00, 0x00, 0x00, 0x01, 0x02, 0x03}
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+
+	d := NewDecoder(bytes.NewReader(input))
+	if _, err := d.Next(); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected truncated error but found %v", err)
+	}
+
+	runtime.ReadMemStats(&after)
+	if alloc := after.TotalAlloc - before.TotalAlloc; alloc > 1<<20 {
+		t.Fatalf("expected less than 1MB allocated but found %d", alloc)
+	}
+}
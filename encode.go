@@ -49,9 +49,13 @@ const (
 	TypeBytes
 
 	// TypeNull is an RLP bytes null (0x80)
+	//
+	// Deprecated: null values have TypeBytes, the same as the parsed 0x80.
 	TypeNull
 
 	// TypeArrayNull is an RLP array null (0xC0)
+	//
+	// Deprecated: null arrays have TypeArray, the same as the parsed 0xC0.
 	TypeArrayNull
 )
 
@@ -79,79 +83,153 @@ type Value struct {
 	// a are the list of objects for the type array
 	a []*Value
 
-	// b is the bytes content of the bytes type
-	b []byte
+	// enc is the full encoding of the value and the content starts after
+	// hdr bytes. It references the input for parsed values. Bytes values
+	// built in an arena own their encoding and the encoding of arrays is
+	// built on demand and dropped when they change.
+	enc []byte
 
 	// l is the length of the value
 	l uint64
 
 	// i is the starting index in the bytes input buffer
 	i uint64
+
+	// lazy decodes the elements of the array on first access.
+	// It is nil once the elements are decoded.
+	lazy *lazyDecoder
+
+	// depth is the number of lists that contain a lazy array
+	depth int
+
+	// parent is the array that contains the value. It is only set for
+	// arrays to update the length of the ancestors when they change.
+	parent *Value
+
+	// hdr is the size of the header in enc
+	hdr uint8
+
+	// strict is set if the value was parsed with ParserOptions.Strict
+	strict bool
+}
+
+// content returns the content of a bytes value or a lazy array.
+// It is nil if the value is empty.
+func (v *Value) content() []byte {
+	if v.l == 0 {
+		return nil
+	}
+	return v.enc[v.hdr:]
+}
+
+// setBytes sets the content of the bytes value v to a copy of b
+func (v *Value) setBytes(b []byte) {
+	v.t = TypeBytes
+	v.l = uint64(len(b))
+	if len(b) == 1 && b[0] <= 0x7F {
+		// single element
+		v.enc = append(v.enc[:0], b[0])
+		v.hdr = 0
+		return
+	}
+	v.enc = v.marshalShortSize(v.enc[:0])
+	v.hdr = uint8(len(v.enc))
+	v.enc = append(v.enc, b...)
 }
 
 // GetString returns string value.
 func (v *Value) GetString() (string, error) {
 	if v.t != TypeBytes {
-		return "", errNoBytes()
+		return "", ErrExpectedBytes
 	}
-	return string(v.b), nil
+	return string(v.content()), nil
 }
 
 // GetElems returns the elements of an array.
 func (v *Value) GetElems() ([]*Value, error) {
 	if v.t != TypeArray {
-		return nil, errNoArray()
+		return nil, ErrExpectedList
+	}
+	if err := v.decode(); err != nil {
+		return nil, err
 	}
 	return v.a, nil
 }
 
+// decode decodes the elements of a lazy array
+func (v *Value) decode() error {
+	if v.lazy == nil {
+		return nil
+	}
+	return v.lazy.decode(v)
+}
+
 // GetBigInt returns big.int value.
 func (v *Value) GetBigInt(b *big.Int) error {
 	if v.t != TypeBytes {
-		return errNoBytes()
+		return ErrExpectedBytes
 	}
-	b.SetBytes(v.b)
+	if err := v.checkCanonInt(); err != nil {
+		return err
+	}
+	b.SetBytes(v.content())
 	return nil
 }
 
 // GetBool returns bool value.
 func (v *Value) GetBool() (bool, error) {
 	if v.t != TypeBytes {
-		return false, errNoBytes()
+		return false, ErrExpectedBytes
+	}
+	if err := v.checkCanonInt(); err != nil {
+		return false, err
 	}
-	if bytes.Equal(v.b, valueTrue.b) {
+	if bytes.Equal(v.content(), valueTrue.content()) {
 		return true, nil
 	}
-	if bytes.Equal(v.b, valueFalse.b) {
+	if bytes.Equal(v.content(), valueFalse.content()) {
 		return false, nil
 	}
-	return false, fmt.Errorf("not a valid bool")
+	return false, ErrInvalidBool
 }
 
-// Raw returns the raw bytes
+// Raw returns the raw bytes of the content. Use Encoding to get
+// the full RLP encoding of the value.
 func (v *Value) Raw() []byte {
-	return v.b
+	return v.Encoding()[v.hdr:]
+}
+
+// Encoding returns the full RLP encoding of the value. For parsed values
+// it references the input of the parser. For arrays built in an arena the
+// encoding is computed on the first call and cached until the value changes.
+func (v *Value) Encoding() []byte {
+	if len(v.enc) == 0 {
+		v.enc = v.MarshalTo(nil)
+		v.hdr = uint8(uint64(len(v.enc)) - v.l)
+	}
+	return v.enc
 }
 
 // Bytes returns the raw bytes.
 func (v *Value) Bytes() ([]byte, error) {
 	if v.t != TypeBytes {
-		return nil, errNoBytes()
+		return nil, ErrExpectedBytes
 	}
-	return v.b, nil
+	return v.content(), nil
 }
 
 // GetBytes returns bytes to dst.
 func (v *Value) GetBytes(dst []byte, bits ...int) ([]byte, error) {
 	if v.t != TypeBytes {
-		return nil, errNoBytes()
+		return nil, ErrExpectedBytes
 	}
+	b := v.content()
 	if len(bits) > 0 {
-		if len(v.b) != bits[0] {
-			return nil, fmt.Errorf("bad length, expected %d but found %d", bits[0], len(v.b))
+		if len(b) != bits[0] {
+			return nil, fmt.Errorf("%w, expected %d but found %d", ErrBadLength, bits[0], len(b))
 		}
 	}
-	dst = append(dst[:0], v.b...)
+	dst = append(dst[:0], b...)
 	return dst, nil
 }
 
@@ -170,38 +248,62 @@ func (v *Value) GetHash(buf []byte) error {
 // GetByte returns a byte
 func (v *Value) GetByte() (byte, error) {
 	if v.t != TypeBytes {
-		return 0, errNoBytes()
+		return 0, ErrExpectedBytes
 	}
-	if len(v.b) != 1 {
-		return 0, fmt.Errorf("bad length, expected 1 but found %d", len(v.b))
+	b := v.content()
+	if len(b) != 1 {
+		return 0, fmt.Errorf("%w, expected 1 but found %d", ErrBadLength, len(b))
 	}
-	return byte(v.b[0]), nil
+	return byte(b[0]), nil
 }
 
 // GetUint64 returns uint64.
 func (v *Value) GetUint64() (uint64, error) {
 	if v.t != TypeBytes {
-		return 0, errNoBytes()
+		return 0, ErrExpectedBytes
+	}
+	b := v.content()
+	if len(b) > 8 {
+		return 0, fmt.Errorf("%w: %d bytes", ErrUint64Range, len(b))
 	}
-	if len(v.b) > 8 {
-		return 0, fmt.Errorf("bytes %d too long for uint64", len(v.b))
+	if err := v.checkCanonInt(); err != nil {
+		return 0, err
 	}
 
 	buf := bufPool.Get().(*[]byte)
-	num := readUint(v.b, *buf)
+	num := readUint(b, *buf)
 	bufPool.Put(buf)
 
 	return num, nil
 }
 
+// checkCanonInt checks that a strict value is a canonical integer
+func (v *Value) checkCanonInt() error {
+	b := v.content()
+	if !v.strict || len(b) == 0 {
+		return nil
+	}
+	if len(b) == 1 && b[0] == 0 {
+		return ErrCanonZero
+	}
+	if b[0] == 0 {
+		return ErrCanonInt
+	}
+	return nil
+}
+
 // Type returns the type of the value
 func (v *Value) Type() Type {
 	return v.t
 }
 
-// Get returns the item at index i in the array
+// Get returns the item at index i in the array or nil if there is
+// no such item. Use GetPath to know why the item does not exist.
 func (v *Value) Get(i int) *Value {
-	if i > len(v.a) {
+	if v.t != TypeArray || v.decode() != nil {
+		return nil
+	}
+	if i < 0 || i >= len(v.a) {
 		return nil
 	}
 	return v.a[i]
@@ -209,6 +311,9 @@ func (v *Value) Get(i int) *Value {
 
 // Elems returns the number of elements if its an array
 func (v *Value) Elems() int {
+	if v.t != TypeArray || v.decode() != nil {
+		return 0
+	}
 	return len(v.a)
 }
 
@@ -221,35 +326,22 @@ func (v *Value) Len() uint64 {
 }
 
 func (v *Value) fullLen() uint64 {
-	// null
-	if v.t == TypeNull || v.t == TypeArrayNull {
-		return 1
-	}
-	// bytes
-	size := v.l
 	if v.t == TypeBytes {
-		if size == 1 && v.b[0] <= 0x7F {
-			return 1
-		} else if size < 56 {
-			return 1 + size
-		} else {
-			return 1 + intsize(size) + size
-		}
-	}
-	// array
-	if size < 56 {
-		return 1 + size
+		// bytes values always have their encoding
+		return uint64(len(v.enc))
 	}
-	return 1 + intsize(size) + size
+	return ListSize(v.l)
 }
 
-// Set sets a value in the array
+// Set sets a value in the array. An array can only be an element
+// of one array at a time.
 func (v *Value) Set(vv *Value) {
-	if v == nil || v.t != TypeArray {
+	if !v.isMutable() {
 		return
 	}
-	v.l += vv.fullLen()
+	v.attach(vv)
 	v.a = append(v.a, vv)
+	v.resize(int64(vv.fullLen()))
 }
 
 func (v *Value) marshalLongSize(dst []byte) []byte {
@@ -279,34 +371,27 @@ func (v *Value) marshalSize(dst []byte, short, long byte) []byte {
 
 // MarshalTo appends marshaled v to dst and returns the result.
 func (v *Value) MarshalTo(dst []byte) []byte {
+	if len(v.enc) != 0 {
+		// bytes values, parsed values and arrays that did not
+		// change since their encoding was computed
+		return append(dst, v.enc...)
+	}
 	switch v.t {
-	case TypeBytes:
-		if len(v.b) == 1 && v.b[0] <= 0x7F {
-			// single element
-			return append(dst, v.b...)
-		}
-		dst = v.marshalShortSize(dst)
-		return append(dst, v.b...)
 	case TypeArray:
 		dst = v.marshalLongSize(dst)
 		for _, vv := range v.a {
 			dst = vv.MarshalTo(dst)
 		}
 		return dst
-	case TypeNull:
-		return append(dst, []byte{0x80}...)
-	case TypeArrayNull:
-		return append(dst, []byte{0xC0}...)
 	default:
 		panic(fmt.Errorf("BUG: unexpected Value type: %d", v.t))
 	}
 }
 
 var (
-	valueArrayNull = &Value{t: TypeArrayNull, l: 1}
-	valueNull      = &Value{t: TypeNull, l: 1}
-	valueFalse     = valueNull
-	valueTrue      = &Value{t: TypeBytes, b: []byte{0x1}, l: 1}
+	valueNull  = &Value{t: TypeBytes, enc: []byte{0x80}, hdr: 1}
+	valueFalse = valueNull
+	valueTrue  = &Value{t: TypeBytes, l: 1, enc: []byte{0x1}}
 )
 
 func intsize(val uint64) uint64 {
@@ -328,11 +413,3 @@ func intsize(val uint64) uint64 {
 	}
 	return 8
 }
-
-func errNoBytes() error {
-	return fmt.Errorf("value is not of type bytes")
-}
-
-func errNoArray() error {
-	return fmt.Errorf("value is not of type array")
-}
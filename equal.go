@@ -0,0 +1,62 @@
+package fastrlp
+
+import (
+	"bytes"
+)
+
+// Equal returns true if v and other have the same content. Values parsed and
+// built in an arena are compared the same way.
+func (v *Value) Equal(other *Value) bool {
+	if len(v.enc) != 0 && len(other.enc) != 0 {
+		// both values have their canonical encoding
+		return bytes.Equal(v.enc, other.enc)
+	}
+	return v.Compare(other) == 0
+}
+
+// Compare returns an integer comparing v and other. The result is 0 if
+// v == other, -1 if v < other and +1 if v > other. Bytes values are lower
+// than arrays and are compared lexicographically. Arrays are compared
+// element by element and then by their number of elements.
+func (v *Value) Compare(other *Value) int {
+	if v.t != other.t {
+		if v.t == TypeBytes {
+			return -1
+		}
+		return 1
+	}
+	if v.t == TypeBytes {
+		return bytes.Compare(v.content(), other.content())
+	}
+	if v.decode() != nil || other.decode() != nil {
+		// compare the encoding of the invalid lazy arrays
+		return bytes.Compare(v.Encoding(), other.Encoding())
+	}
+	for i := 0; i < len(v.a) && i < len(other.a); i++ {
+		if c := v.a[i].Compare(other.a[i]); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case len(v.a) < len(other.a):
+		return -1
+	case len(v.a) > len(other.a):
+		return 1
+	}
+	return 0
+}
+
+// Keccak returns the keccak256 hash of the encoding of v.
+func (v *Value) Keccak() (hash [32]byte) {
+	k := keccakPool.Get().(*Keccak)
+	k.Reset()
+	if len(v.enc) != 0 {
+		k.Write(v.enc)
+	} else {
+		k.buf = v.MarshalTo(k.buf[:0])
+		k.Write(k.buf)
+	}
+	copy(hash[:], k.Read())
+	keccakPool.Put(k)
+	return
+}
@@ -0,0 +1,134 @@
+package fastrlp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestValueEqual(t *testing.T) {
+	a := &Arena{}
+	p0, p1 := &Parser{}, &Parser{}
+
+	for i := 0; i < 100; i++ {
+		v := generateRandom()
+		buf := v.MarshalTo(nil)
+
+		pv, err := p0.Parse(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lv, err := p1.ParseLazy(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		vals := []*Value{v, pv, lv, pv.CopyTo(a)}
+		for _, v0 := range vals {
+			for _, v1 := range vals {
+				if !v0.Equal(v1) || v0.Compare(v1) != 0 {
+					t.Fatal("values should be equal")
+				}
+			}
+		}
+
+		// different value
+		other := a.NewArray()
+		other.Set(v)
+		if v.Equal(other) || pv.Equal(other) {
+			t.Fatal("values should not be equal")
+		}
+	}
+}
+
+func TestValueEqualNull(t *testing.T) {
+	a := &Arena{}
+	p := &Parser{}
+
+	v, err := p.Parse([]byte{0x80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, vv := range []*Value{a.NewNull(), a.NewBytes(nil), a.NewUint(0), a.NewFalse()} {
+		if !v.Equal(vv) || !vv.Equal(v) {
+			t.Fatal("null values should be equal")
+		}
+	}
+	if !a.NewNullArray().Equal(a.NewArray()) {
+		t.Fatal("null arrays should be equal")
+	}
+}
+
+func TestValueCompare(t *testing.T) {
+	a := &Arena{}
+
+	list := func(vals ...*Value) *Value {
+		v := a.NewArray()
+		for _, vv := range vals {
+			v.Set(vv)
+		}
+		return v
+	}
+
+	// sorted values
+	vals := []*Value{
+		a.NewBytes(nil),
+		a.NewUint(1),
+		a.NewString("cat"),
+		a.NewString("dog"),
+		list(),
+		list(a.NewUint(1)),
+		list(a.NewUint(1), a.NewUint(1)),
+		list(a.NewUint(2)),
+		list(list()),
+	}
+	for i := range vals {
+		for j := range vals {
+			expected := 0
+			if i < j {
+				expected = -1
+			} else if i > j {
+				expected = 1
+			}
+			if c := vals[i].Compare(vals[j]); c != expected {
+				t.Fatalf("compare %d %d: expected %d but found %d", i, j, expected, c)
+			}
+		}
+	}
+}
+
+func TestValueKeccak(t *testing.T) {
+	a := &Arena{}
+	p := &Parser{}
+
+	v := a.NewArray()
+	v.Set(generateRandom())
+	pv, err := p.Parse(v.MarshalTo(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h0, h1 := v.Keccak(), pv.Keccak()
+	if h0 != h1 {
+		t.Fatal("bad hash")
+	}
+	if !bytes.Equal(h0[:], p.Hash(nil, pv)) {
+		t.Fatal("bad hash")
+	}
+
+	pv.Set(a.NewUint(1))
+	if pv.Keccak() == h1 {
+		t.Fatal("hash should change")
+	}
+}
+
+func BenchmarkValueKeccak(b *testing.B) {
+	a := &Arena{}
+	v := a.NewArray()
+	v.Set(generateRandom())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.Keccak()
+	}
+}
@@ -0,0 +1,132 @@
+package fastrlp
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrTruncated is returned when the input ends before the value is complete.
+	ErrTruncated = errors.New("value size exceeds available input length")
+
+	// ErrNonCanonicalSize is returned when the size of a value is not encoded
+	// in its shortest form.
+	ErrNonCanonicalSize = errors.New("non-canonical size information")
+
+	// ErrElemTooLarge is returned when an element is larger than its containing list.
+	ErrElemTooLarge = errors.New("element is larger than containing list")
+
+	// ErrSizeOverflow is returned when the size in a header is too large for any input.
+	ErrSizeOverflow = errors.New("value size overflows")
+
+	// ErrTrailingData is returned when there are bytes left after the value.
+	ErrTrailingData = errors.New("input contains more than one value")
+
+	// ErrCanonInt is returned when an integer is encoded with leading zero bytes.
+	ErrCanonInt = errors.New("non-canonical integer (leading zero bytes)")
+
+	// ErrCanonZero is returned when the integer zero is encoded as a single 0x00 byte.
+	ErrCanonZero = errors.New("non-canonical integer (zero encoded as 0x00)")
+
+	// ErrExpectedBytes is returned when a bytes value is expected.
+	ErrExpectedBytes = errors.New("value is not of type bytes")
+
+	// ErrExpectedList is returned when an array value is expected.
+	ErrExpectedList = errors.New("value is not of type array")
+
+	// ErrIndexOutOfRange is returned when an index is not found in an array.
+	ErrIndexOutOfRange = errors.New("index out of range")
+
+	// ErrBadLength is returned when a bytes value does not have the expected length.
+	ErrBadLength = errors.New("bad length")
+
+	// ErrUint64Range is returned when an integer does not fit in an uint64.
+	ErrUint64Range = errors.New("value too long for uint64")
+
+	// ErrInvalidBool is returned when a value is not a valid bool.
+	ErrInvalidBool = errors.New("not a valid bool")
+
+	// ErrBadNotation is returned when the text notation of a value is not valid.
+	ErrBadNotation = errors.New("bad notation")
+
+	// ErrBadJSON is returned when a JSON value cannot be converted to a value.
+	ErrBadJSON = errors.New("bad json value")
+
+	// ErrUnsupportedType is returned when a Go value cannot be converted to a value.
+	ErrUnsupportedType = errors.New("unsupported type")
+)
+
+var (
+	// ErrMaxDepth is returned when the lists are nested deeper than ParserOptions.MaxDepth.
+	ErrMaxDepth = errors.New("max depth exceeded")
+
+	// ErrMaxListElems is returned when a list has more elements than ParserOptions.MaxListElems.
+	ErrMaxListElems = errors.New("max list elements exceeded")
+
+	// ErrMaxBytesLen is returned when a bytes value is longer than ParserOptions.MaxBytesLen.
+	ErrMaxBytesLen = errors.New("max bytes length exceeded")
+
+	// ErrMaxInputSize is returned when the input is larger than ParserOptions.MaxInputSize.
+	ErrMaxInputSize = errors.New("max input size exceeded")
+)
+
+// ParseError is the error returned when the input is not a valid RLP encoding.
+type ParseError struct {
+	// Offset is the position in the input of the value that failed
+	Offset uint64
+
+	// Path are the indexes of the value that failed starting from the root
+	Path []int
+
+	// Kind is the sentinel error that describes the failure (i.e. ErrTruncated)
+	Kind error
+
+	// Err is an optional error with more details about the failure
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	err := e.Err
+	if err == nil {
+		err = e.Kind
+	}
+	if len(e.Path) == 0 {
+		return fmt.Sprintf("cannot parse RLP at offset %d: %s", e.Offset, err)
+	}
+	return fmt.Sprintf("cannot parse RLP at offset %d (path %v): %s", e.Offset, e.Path, err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	if e.Err != nil {
+		return e.Err
+	}
+	return e.Kind
+}
+
+// Is reports whether target is the kind of the error.
+func (e *ParseError) Is(target error) bool {
+	return e.Kind == target
+}
+
+// LimitError is returned when the input exceeds one of the ParserOptions limits.
+type LimitError struct {
+	// Err is one of the ErrMax* errors
+	Err error
+
+	// Limit is the value of the exceeded limit
+	Limit uint64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s (limit %d)", e.Err, e.Limit)
+}
+
+// Unwrap returns the ErrMax* error.
+func (e *LimitError) Unwrap() error {
+	return e.Err
+}
+
+func newLimitError(err error, limit uint64) *ParseError {
+	return &ParseError{Kind: err, Err: &LimitError{Err: err, Limit: limit}}
+}
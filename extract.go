@@ -0,0 +1,141 @@
+package fastrlp
+
+import (
+	"fmt"
+)
+
+// Wildcard matches every element of an array in a Path.
+const Wildcard = -1
+
+// Path is a list of array indexes starting from the root value.
+type Path []int
+
+// Extract returns the raw encodings of the values at the given paths
+// without decoding the rest of the input. The subtrees that are not
+// part of any path are skipped using their size.
+//
+// For each path it returns the list of matched values in order,
+// which has more than one element if the path includes a Wildcard.
+func Extract(b []byte, paths ...Path) ([][][]byte, error) {
+	if err := extractRoot(b); err != nil {
+		return nil, err
+	}
+
+	res := make([][][]byte, len(paths))
+	for indx, path := range paths {
+		err := extract(b, path, func(offset uint64, raw []byte) error {
+			res[indx] = append(res[indx], raw)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// Extract parses only the values at the given paths.
+// For each path it returns the list of matched values in order.
+func (p *Parser) Extract(b []byte, paths ...Path) ([][]*Value, error) {
+	if err := p.checkInputSize(len(b)); err != nil {
+		return nil, err
+	}
+	if err := extractRoot(b); err != nil {
+		return nil, err
+	}
+
+	p.c.reset()
+	p.buf = append(p.buf[:0], b...)
+
+	res := make([][]*Value, len(paths))
+	for indx, path := range paths {
+		err := extract(p.buf, path, func(offset uint64, raw []byte) error {
+			p.c.indx = offset
+			v, _, err := p.parseValue(raw)
+			if err != nil {
+				return err
+			}
+			res[indx] = append(res[indx], v)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// extractRoot validates the header of the root value
+func extractRoot(b []byte) error {
+	_, hdr, size, err := readHeader(b)
+	if err != nil {
+		return &ParseError{Kind: err}
+	}
+	if hdr+size != uint64(len(b)) {
+		return &ParseError{Offset: hdr + size, Kind: ErrTrailingData}
+	}
+	return nil
+}
+
+// extract calls fn with the values that match the path in the value at the
+// start of b. The subtrees that are not part of the path are skipped.
+func extract(b []byte, path Path, fn func(offset uint64, raw []byte) error) error {
+	var w walker
+	w.reset(b, 0, 0, ParserOptions{})
+
+	for {
+		// depth is the number of arrays that contain the next value
+		// and indx is its index in the current array
+		depth, indx := w.n, 0
+		if depth != 0 {
+			if elem := path[depth-1]; elem != Wildcard {
+				if err := w.skipTo(elem); err != nil {
+					return err
+				}
+			}
+			indx = w.index()
+		}
+		match := depth == 0 || path[depth-1] == Wildcard || path[depth-1] == indx
+
+		// only the arrays in the path are entered
+		var ev walkEvent
+		var pos, hdr, size uint64
+		var err error
+		if match && depth < len(path) {
+			ev, pos, hdr, size, err = w.next()
+		} else {
+			ev, pos, hdr, size, err = w.nextSkip()
+		}
+		if err != nil {
+			return err
+		}
+
+		if ev == walkLeave {
+			// indx is the number of elements of the array
+			if elem := path[depth-1]; elem != Wildcard && indx <= elem {
+				return fmt.Errorf("%w: index %d in array of %d elements at offset %d", ErrIndexOutOfRange, elem, indx, pos)
+			}
+			if depth > 1 && path[depth-2] != Wildcard {
+				// the array is the only match in its parent
+				w.skipRest()
+			}
+		} else if match {
+			if depth < len(path) {
+				if ev != walkEnter {
+					return fmt.Errorf("%w at offset %d", ErrExpectedList, pos)
+				}
+			} else {
+				if err := fn(pos, b[pos:pos+hdr+size]); err != nil {
+					return w.withPath(err)
+				}
+				if depth != 0 && path[depth-1] != Wildcard {
+					w.skipRest()
+				}
+			}
+		}
+
+		if w.done() {
+			return nil
+		}
+	}
+}
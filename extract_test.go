@@ -0,0 +1,113 @@
+package fastrlp
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExtract(t *testing.T) {
+	a := &Arena{}
+
+	// block with a header and a list of transactions
+	header := a.NewArray()
+	for i := 0; i < 10; i++ {
+		header.Set(a.NewUint(uint64(i)))
+	}
+	txs := a.NewArray()
+	for i := 0; i < 5; i++ {
+		tx := a.NewArray()
+		tx.Set(a.NewUint(uint64(100 + i)))
+		tx.Set(a.NewString("data"))
+		txs.Set(tx)
+	}
+	block := a.NewArray()
+	block.Set(header)
+	block.Set(txs)
+	buf := block.MarshalTo(nil)
+
+	res, err := Extract(buf, Path{0, 8}, Path{1, Wildcard, 0}, Path{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 3 {
+		t.Fatal("bad")
+	}
+	if len(res[0]) != 1 || !bytes.Equal(res[0][0], a.NewUint(8).MarshalTo(nil)) {
+		t.Fatal("bad header number")
+	}
+	if len(res[1]) != 5 {
+		t.Fatal("bad nonces")
+	}
+	for i, raw := range res[1] {
+		if !bytes.Equal(raw, a.NewUint(uint64(100+i)).MarshalTo(nil)) {
+			t.Fatal("bad nonce")
+		}
+	}
+	if len(res[2]) != 1 || !bytes.Equal(res[2][0], txs.MarshalTo(nil)) {
+		t.Fatal("bad txs")
+	}
+
+	p := &Parser{}
+	vals, err := p.Extract(buf, Path{0, 8}, Path{1, Wildcard, 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num, err := vals[0][0].GetUint64(); err != nil || num != 8 {
+		t.Fatalf("bad header number %d %v", num, err)
+	}
+	for i, v := range vals[1] {
+		if num, err := v.GetUint64(); err != nil || num != uint64(100+i) {
+			t.Fatalf("bad nonce %d %v", num, err)
+		}
+		if !bytes.Equal(p.Raw(v), res[1][i]) {
+			t.Fatal("bad raw")
+		}
+	}
+
+	// not found
+	if _, err := Extract(buf, Path{0, 10}); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("expected out of range error but found %v", err)
+	}
+	if _, err := Extract(buf, Path{0, 1, 0}); !errors.Is(err, ErrExpectedList) {
+		t.Fatalf("expected list error but found %v", err)
+	}
+}
+
+func TestExtractInvalid(t *testing.T) {
+	// the second element of the first list overflows the list
+	buf := []byte{0xC5, 0xC2, 0x01, 0x82, 0x01, 0x01}
+	if _, err := Extract(buf, Path{0, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Extract(buf, Path{0, 1}); !errors.Is(err, ErrElemTooLarge) {
+		t.Fatalf("expected elem too large error but found %v", err)
+	}
+	if _, err := Extract(append(buf, 0x01), Path{0}); !errors.Is(err, ErrTrailingData) {
+		t.Fatalf("expected trailing data error but found %v", err)
+	}
+
+	// the errors of the extracted values have the path from the root
+	buf = []byte{0xC6, 0xC5, 0x01, 0x83, 0x64, 0x6F, 0x67}
+	opts := ParserOptions{MaxBytesLen: 2}
+	_, parseErr := NewParser(opts).Parse(buf)
+	if parseErr == nil {
+		t.Fatal("it should fail")
+	}
+	if _, err := NewParser(opts).Extract(buf, Path{0}); !reflect.DeepEqual(err, parseErr) {
+		t.Fatalf("expected '%v' but found '%v'", parseErr, err)
+	}
+}
+
+func BenchmarkExtract(b *testing.B) {
+	buf := benchmarkParserInput()
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		if _, err := Extract(buf, Path{50, 8}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
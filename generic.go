@@ -0,0 +1,103 @@
+package fastrlp
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+)
+
+// Interface returns v as a plain Go value. Bytes values are returned as
+// []byte, which reference the content of the value, and arrays as
+// []interface{}. It returns nil for arrays with elements that cannot be decoded.
+func (v *Value) Interface() interface{} {
+	if v.t == TypeBytes {
+		return v.content()
+	}
+	if v.decode() != nil {
+		return nil
+	}
+	res := make([]interface{}, len(v.a))
+	for i, vv := range v.a {
+		res[i] = vv.Interface()
+	}
+	return res
+}
+
+// NewFromInterface returns a new value from a plain Go value. It accepts
+// []byte, string, unsigned integers, *big.Int, bool, Marshaler, *Value
+// and slices and arrays of any of them. Slices and arrays of bytes are
+// converted to bytes values and the rest of slices and arrays to arrays.
+func (a *Arena) NewFromInterface(x interface{}) (*Value, error) {
+	switch obj := x.(type) {
+	case *Value:
+		return obj, nil
+	case Marshaler:
+		return obj.MarshalRLPWith(a)
+	case []byte:
+		return a.NewBytes(obj), nil
+	case string:
+		return a.NewString(obj), nil
+	case bool:
+		return a.NewBool(obj), nil
+	case uint:
+		return a.NewUint(uint64(obj)), nil
+	case uint8:
+		return a.NewUint(uint64(obj)), nil
+	case uint16:
+		return a.NewUint(uint64(obj)), nil
+	case uint32:
+		return a.NewUint(uint64(obj)), nil
+	case uint64:
+		return a.NewUint(obj), nil
+	case *big.Int:
+		if obj != nil && obj.Sign() < 0 {
+			return nil, fmt.Errorf("%w: negative big.Int", ErrUnsupportedType)
+		}
+		return a.NewBigInt(obj), nil
+	case []interface{}:
+		v := a.NewArray()
+		for indx, elem := range obj {
+			vv, err := a.NewFromInterface(elem)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", indx, err)
+			}
+			v.Set(vv)
+		}
+		return v, nil
+	}
+	return a.newFromReflect(reflect.ValueOf(x))
+}
+
+// newFromReflect converts the named types and the slices and arrays that
+// are not handled by NewFromInterface
+func (a *Arena) newFromReflect(rv reflect.Value) (*Value, error) {
+	switch rv.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return a.NewUint(rv.Uint()), nil
+	case reflect.String:
+		return a.NewString(rv.String()), nil
+	case reflect.Bool:
+		return a.NewBool(rv.Bool()), nil
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			// the elements may have a named byte type
+			buf := make([]byte, rv.Len())
+			for i := range buf {
+				buf[i] = byte(rv.Index(i).Uint())
+			}
+			return a.NewBytes(buf), nil
+		}
+		v := a.NewArray()
+		for indx := 0; indx < rv.Len(); indx++ {
+			vv, err := a.NewFromInterface(rv.Index(indx).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", indx, err)
+			}
+			v.Set(vv)
+		}
+		return v, nil
+	case reflect.Invalid:
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, rv.Type())
+}
@@ -0,0 +1,112 @@
+package fastrlp
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestValueInterface(t *testing.T) {
+	v, err := ParseNotation(`["0x01", ["cat", "dog"], [], ""]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []interface{}{
+		[]byte{0x1},
+		[]interface{}{[]byte("cat"), []byte("dog")},
+		[]interface{}{},
+		[]byte(nil),
+	}
+	if found := v.Interface(); !reflect.DeepEqual(found, expected) {
+		t.Fatalf("expected %v but found %v", expected, found)
+	}
+
+	// round trip
+	a := &Arena{}
+	p := &Parser{}
+	for i := 0; i < 100; i++ {
+		pv, err := p.Parse(generateRandom().MarshalTo(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		vv, err := a.NewFromInterface(pv.Interface())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !vv.Equal(pv) {
+			t.Fatal("bad")
+		}
+	}
+}
+
+func TestNewFromInterface(t *testing.T) {
+	type myUint uint16
+	type myString string
+	type myByte uint8
+
+	simple := &Simple{Data1: []byte{0x1}, Data3: 2}
+
+	cases := []struct {
+		x        interface{}
+		notation string
+	}{
+		{[]byte{0x1, 0x2}, `"0x0102"`},
+		{"dog", `"dog"`},
+		{true, `"0x01"`},
+		{false, `""`},
+		{uint(1), `"0x01"`},
+		{uint8(2), `"0x02"`},
+		{uint16(300), `300`},
+		{uint32(0), `""`},
+		{uint64(1000), `1000`},
+		{myUint(300), `300`},
+		{myString("cat"), `"cat"`},
+		{big.NewInt(1000), `1000`},
+		{(*big.Int)(nil), `""`},
+		{[4]byte{0xde, 0xad, 0xbe, 0xef}, `"0xdeadbeef"`},
+		{[2]myByte{0x1, 0x2}, `"0x0102"`},
+		{[]myByte{0x1, 0x2}, `"0x0102"`},
+		{[]interface{}{}, `[]`},
+		{[]interface{}{"cat", []interface{}{uint64(1)}, []byte{}}, `["cat", [1], ""]`},
+		{[]string{"cat", "dog"}, `["cat", "dog"]`},
+		{[][]byte{{0x1}, {0x2}}, `["0x01", "0x02"]`},
+		{[2][]uint64{{1}, {2, 3}}, `[[1], [2, 3]]`},
+		{simple, `["0x01", [], 2]`},
+		{[]*Simple{simple}, `[["0x01", [], 2]]`},
+	}
+
+	a := &Arena{}
+	for _, c := range cases {
+		v, err := a.NewFromInterface(c.x)
+		if err != nil {
+			t.Fatalf("%v: %v", c.x, err)
+		}
+		expected, err := ParseNotation(c.notation)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !v.Equal(expected) {
+			t.Fatalf("%v: expected %s but found %s", c.x, expected, v)
+		}
+	}
+}
+
+func TestNewFromInterfaceError(t *testing.T) {
+	cases := []interface{}{
+		nil,
+		1,
+		int64(1),
+		big.NewInt(-1),
+		map[string]string{},
+		[]interface{}{"cat", 1.5},
+		[]int{1},
+	}
+
+	a := &Arena{}
+	for _, c := range cases {
+		if _, err := a.NewFromInterface(c); !errors.Is(err, ErrUnsupportedType) {
+			t.Fatalf("%v: expected unsupported type but found %v", c, err)
+		}
+	}
+}
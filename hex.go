@@ -0,0 +1,111 @@
+package fastrlp
+
+import (
+	"encoding/hex"
+)
+
+const hextable = "0123456789abcdef"
+
+// ParseHex parses a complete rlp encoding in hex, with or without the 0x prefix.
+// The input is decoded into the buffer of the parser without intermediate copies.
+func (p *Parser) ParseHex(s string) (*Value, error) {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	if len(s)%2 != 0 {
+		return nil, hex.ErrLength
+	}
+	if err := p.checkInputSize(len(s) / 2); err != nil {
+		return nil, err
+	}
+
+	p.buf = p.buf[:0]
+	for i := 0; i < len(s); i += 2 {
+		hi, ok := fromHexChar(s[i])
+		if !ok {
+			return nil, hex.InvalidByteError(s[i])
+		}
+		lo, ok := fromHexChar(s[i+1])
+		if !ok {
+			return nil, hex.InvalidByteError(s[i+1])
+		}
+		p.buf = append(p.buf, hi<<4|lo)
+	}
+	return p.parseBuf()
+}
+
+func fromHexChar(c byte) (byte, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return c - '0', true
+	case 'a' <= c && c <= 'f':
+		return c - 'a' + 10, true
+	case 'A' <= c && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// MarshalHexTo appends the marshaled v in hex with the 0x prefix to dst
+// and returns the result.
+func (v *Value) MarshalHexTo(dst []byte) []byte {
+	start := len(dst)
+	dst = v.MarshalTo(dst)
+	return expandHex(dst, start)
+}
+
+// expandHex replaces dst[start:] with its hex encoding with the 0x prefix.
+// The bytes are expanded in place from the end so that no byte is
+// overwritten before it is encoded.
+func expandHex(dst []byte, start int) []byte {
+	n := len(dst) - start
+	for i := 0; i < n+2; i++ {
+		dst = append(dst, 0)
+	}
+	for i := n - 1; i >= 0; i-- {
+		c := dst[start+i]
+		dst[start+2+2*i] = hextable[c>>4]
+		dst[start+3+2*i] = hextable[c&0x0f]
+	}
+	dst[start] = '0'
+	dst[start+1] = 'x'
+	return dst
+}
+
+// GetHex appends the content of a bytes value in hex with the 0x prefix to dst.
+func (v *Value) GetHex(dst []byte) ([]byte, error) {
+	if v.t != TypeBytes {
+		return nil, ErrExpectedBytes
+	}
+	start := len(dst)
+	dst = append(dst, v.content()...)
+	return expandHex(dst, start), nil
+}
+
+// GetHexBig appends the integer value in hex with the 0x prefix and without
+// leading zeros to dst, the quantity encoding of JSON-RPC (i.e. 0x0 or 0x12c).
+func (v *Value) GetHexBig(dst []byte) ([]byte, error) {
+	if v.t != TypeBytes {
+		return nil, ErrExpectedBytes
+	}
+	if err := v.checkCanonInt(); err != nil {
+		return nil, err
+	}
+
+	b := v.content()
+	for len(b) > 0 && b[0] == 0 {
+		b = b[1:]
+	}
+	dst = append(dst, '0', 'x')
+	if len(b) == 0 {
+		return append(dst, '0'), nil
+	}
+	if b[0] < 0x10 {
+		dst = append(dst, hextable[b[0]])
+		b = b[1:]
+	}
+	for _, c := range b {
+		dst = append(dst, hextable[c>>4], hextable[c&0x0f])
+	}
+	return dst, nil
+}
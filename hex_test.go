@@ -0,0 +1,111 @@
+package fastrlp
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestParseHex(t *testing.T) {
+	p := &Parser{}
+
+	for _, s := range []string{"0xc6827a77c10401", "0XC6827A77C10401", "c6827a77c10401"} {
+		v, err := p.ParseHex(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if str := v.String(); str != `["zw", ["0x04"], "0x01"]` {
+			t.Fatalf("bad: %s", str)
+		}
+	}
+
+	if _, err := p.ParseHex("0xc"); err != hex.ErrLength {
+		t.Fatalf("expected length error but found %v", err)
+	}
+	if _, err := p.ParseHex("0xzz"); !errors.As(err, new(hex.InvalidByteError)) {
+		t.Fatalf("expected invalid byte but found %v", err)
+	}
+	if _, err := p.ParseHex("0xc1"); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected truncated but found %v", err)
+	}
+
+	p.SetOptions(ParserOptions{MaxInputSize: 2})
+	if _, err := p.ParseHex("0xc3010203"); !errors.Is(err, ErrMaxInputSize) {
+		t.Fatalf("expected max input size but found %v", err)
+	}
+}
+
+func TestMarshalHex(t *testing.T) {
+	p := &Parser{}
+	for i := 0; i < 100; i++ {
+		v := generateRandom()
+		enc := v.MarshalTo(nil)
+
+		dst := v.MarshalHexTo([]byte("prefix"))
+		if string(dst) != "prefix0x"+hex.EncodeToString(enc) {
+			t.Fatal("bad")
+		}
+
+		pv, err := p.ParseHex(string(dst[len("prefix"):]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pv.Equal(v) {
+			t.Fatal("bad")
+		}
+	}
+
+	v, _ := p.ParseHex("0xc20102")
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = v.MarshalHexTo(buf[:0])
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations but found %f", allocs)
+	}
+}
+
+func TestGetHex(t *testing.T) {
+	a := &Arena{}
+
+	cases := []struct {
+		v   *Value
+		hex string
+		big string
+	}{
+		{a.NewNull(), "0x", "0x0"},
+		{a.NewUint(1), "0x01", "0x1"},
+		{a.NewUint(300), "0x012c", "0x12c"},
+		{a.NewUint(0x1000), "0x1000", "0x1000"},
+		{a.NewBytes([]byte{0x0, 0x1}), "0x0001", "0x1"},
+	}
+	for _, c := range cases {
+		buf, err := c.v.GetHex(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != c.hex {
+			t.Fatalf("expected %s but found %s", c.hex, buf)
+		}
+		if buf, err = c.v.GetHexBig(buf[:0]); err != nil {
+			t.Fatal(err)
+		}
+		if string(buf) != c.big {
+			t.Fatalf("expected %s but found %s", c.big, buf)
+		}
+	}
+
+	if _, err := a.NewArray().GetHex(nil); err != ErrExpectedBytes {
+		t.Fatal("expected bytes error")
+	}
+
+	// strict values reject non-canonical integers
+	p := NewParser(ParserOptions{Strict: true})
+	v, err := p.ParseHex("0x820001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.GetHexBig(nil); err != ErrCanonInt {
+		t.Fatalf("expected canon int but found %v", err)
+	}
+}
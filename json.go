@@ -0,0 +1,120 @@
+package fastrlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// MarshalJSON implements json.Marshaler. Bytes values are encoded
+// as hex strings with the 0x prefix and arrays as JSON arrays.
+func (v *Value) MarshalJSON() ([]byte, error) {
+	return v.appendJSON(nil)
+}
+
+func (v *Value) appendJSON(dst []byte) ([]byte, error) {
+	if v.t == TypeBytes {
+		dst = append(dst, `"0x`...)
+		dst = appendHex(dst, v.content())
+		return append(dst, '"'), nil
+	}
+	if err := v.decode(); err != nil {
+		return nil, err
+	}
+	dst = append(dst, '[')
+	for i, vv := range v.a {
+		if i != 0 {
+			dst = append(dst, ',')
+		}
+		var err error
+		if dst, err = vv.appendJSON(dst); err != nil {
+			return nil, err
+		}
+	}
+	return append(dst, ']'), nil
+}
+
+// JSONOption is an option for FromJSON.
+type JSONOption func(c *jsonConfig)
+
+type jsonConfig struct {
+	bigInt bool
+}
+
+// WithBigIntNotation parses the strings with the # prefix as decimal
+// integers, the notation used by the ethereum/tests fixtures for
+// integers that do not fit in a JSON number (i.e. "#83729609699884896815286331701780722").
+func WithBigIntNotation() JSONOption {
+	return func(c *jsonConfig) {
+		c.bigInt = true
+	}
+}
+
+// FromJSON builds a value in the arena from its JSON representation.
+// Arrays are converted to arrays and strings to bytes values, either
+// decoded from hex if they have the 0x prefix or as their text otherwise.
+// Non-negative JSON integers are converted to their big endian bytes.
+func (a *Arena) FromJSON(b []byte, opts ...JSONOption) (*Value, error) {
+	var c jsonConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var x interface{}
+	if err := dec.Decode(&x); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: trailing data after the value", ErrBadJSON)
+	}
+	return a.fromJSON(x, &c)
+}
+
+func (a *Arena) fromJSON(x interface{}, c *jsonConfig) (*Value, error) {
+	switch obj := x.(type) {
+	case []interface{}:
+		v := a.NewArray()
+		for indx, elem := range obj {
+			vv, err := a.fromJSON(elem, c)
+			if err != nil {
+				return nil, fmt.Errorf("index %d: %w", indx, err)
+			}
+			v.Set(vv)
+		}
+		return v, nil
+
+	case string:
+		if strings.HasPrefix(obj, "0x") {
+			buf, err := hex.DecodeString(obj[2:])
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrBadJSON, err)
+			}
+			return a.NewBytes(buf), nil
+		}
+		if c.bigInt && strings.HasPrefix(obj, "#") {
+			return a.fromJSONInt(obj[1:])
+		}
+		return a.NewString(obj), nil
+
+	case json.Number:
+		return a.fromJSONInt(obj.String())
+
+	default:
+		return nil, fmt.Errorf("%w: unexpected %T", ErrBadJSON, x)
+	}
+}
+
+func (a *Arena) fromJSONInt(s string) (*Value, error) {
+	num, ok := new(big.Int).SetString(s, 10)
+	if !ok || num.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %q is not an unsigned integer", ErrBadJSON, s)
+	}
+	return a.NewBigInt(num), nil
+}
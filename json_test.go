@@ -0,0 +1,116 @@
+package fastrlp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	v, err := ParseNotation(`["0x01", ["cat", "dog"], 300, [], ""]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `["0x01",["0x636174","0x646f67"],"0x012c",[],"0x"]`
+	if string(data) != expected {
+		t.Fatalf("expected %s but found %s", expected, data)
+	}
+
+	// as part of another struct
+	obj := struct {
+		V *Value `json:"v"`
+	}{V: v}
+	if data, err = json.Marshal(obj); err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"v":`+expected+`}` {
+		t.Fatalf("bad: %s", data)
+	}
+}
+
+func TestFromJSON(t *testing.T) {
+	cases := []struct {
+		json string
+		enc  string
+		opts []JSONOption
+	}{
+		{`"0x"`, "80", nil},
+		{`""`, "80", nil},
+		{`"dog"`, "83646f67", nil},
+		{`"0x00"`, "00", nil},
+		{`0`, "80", nil},
+		{`1000`, "8203e8", nil},
+		{`[]`, "c0", nil},
+		{`["zw", [4], 1]`, "c6827a77c10401", nil},
+		{`"#1000"`, "852331303030", nil},
+		{`"#1000"`, "8203e8", []JSONOption{WithBigIntNotation()}},
+		{`"#83729609699884896815286331701780722"`, "8f102030405060708090a0b0c0d0e0f2", []JSONOption{WithBigIntNotation()}},
+	}
+
+	a := &Arena{}
+	for _, c := range cases {
+		v, err := a.FromJSON([]byte(c.json), c.opts...)
+		if err != nil {
+			t.Fatalf("%s: %v", c.json, err)
+		}
+		if enc := hex.EncodeToString(v.MarshalTo(nil)); enc != c.enc {
+			t.Fatalf("%s: expected %s but found %s", c.json, c.enc, enc)
+		}
+	}
+}
+
+func TestFromJSONError(t *testing.T) {
+	cases := []string{
+		`"0x0"`,
+		`-1`,
+		`1.5`,
+		`true`,
+		`null`,
+		`{}`,
+		`[1, {}]`,
+		`[] []`,
+	}
+
+	a := &Arena{}
+	for _, c := range cases {
+		if _, err := a.FromJSON([]byte(c), WithBigIntNotation()); !errors.Is(err, ErrBadJSON) {
+			t.Fatalf("%s: expected bad json but found %v", c, err)
+		}
+	}
+	if _, err := a.FromJSON([]byte(`"#-1"`), WithBigIntNotation()); !errors.Is(err, ErrBadJSON) {
+		t.Fatal("expected bad json")
+	}
+	if _, err := a.FromJSON([]byte(`[`)); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	a := &Arena{}
+	p := &Parser{}
+	for i := 0; i < 100; i++ {
+		v := generateRandom()
+
+		pv, err := p.Parse(v.MarshalTo(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(pv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vv, err := a.FromJSON(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !vv.Equal(pv) {
+			t.Fatal("bad")
+		}
+	}
+}
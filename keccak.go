@@ -2,6 +2,7 @@ package fastrlp
 
 import (
 	"hash"
+	"sync"
 
 	"golang.org/x/crypto/sha3"
 )
@@ -52,3 +53,10 @@ func newKeccak(hash hashImpl) *Keccak {
 func NewKeccak256() *Keccak {
 	return newKeccak(sha3.NewLegacyKeccak256().(hashImpl))
 }
+
+// keccakPool is a pool of keccak256 hashes
+var keccakPool = sync.Pool{
+	New: func() interface{} {
+		return NewKeccak256()
+	},
+}
@@ -0,0 +1,95 @@
+package fastrlp
+
+// ParseLazy parses a complete rlp encoding but only decodes the elements
+// of the root array. The elements of the nested arrays are decoded the first
+// time they are accessed with GetElems, Get or Elems and cached in the parser.
+//
+// Errors in the nested arrays are only reported when they are accessed and
+// their path is relative to the array being decoded.
+func (p *Parser) ParseLazy(b []byte) (*Value, error) {
+	if err := p.checkInputSize(len(b)); err != nil {
+		return nil, err
+	}
+
+	p.c.reset()
+	p.buf = append(p.buf[:0], b...)
+
+	p.w.reset(p.buf, 0, 0, p.opts)
+	ev, _, hdr, _, err := p.w.nextSkip()
+	if err != nil {
+		return nil, err
+	}
+	if p.w.pos != uint64(len(p.buf)) {
+		return nil, &ParseError{Offset: p.w.pos, Kind: ErrTrailingData}
+	}
+
+	p.lazy.c = &p.c
+	p.lazy.opts = p.opts
+	v := p.lazy.newValue(ev, p.buf[:len(p.buf):len(p.buf)], 0, hdr, 0)
+	if err := v.decode(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// lazyDecoder decodes the elements of lazy arrays on first access.
+// The elements reference the encoding of the array and they are
+// allocated in the cache of the Parser or the Arena that owns it.
+type lazyDecoder struct {
+	c    *cache
+	opts ParserOptions
+	w    walker
+}
+
+// newValue returns the value with the encoding enc, which is at the given
+// offset of the input. Arrays are returned without decoding its elements.
+func (d *lazyDecoder) newValue(ev walkEvent, enc []byte, offset, hdr uint64, depth int) *Value {
+	v := d.c.getValue()
+	v.enc = enc
+	v.hdr = uint8(hdr)
+	v.l = uint64(len(enc)) - hdr
+	v.i = offset
+	v.lazy = nil
+	v.parent = nil
+
+	if ev == walkBytes {
+		v.t = TypeBytes
+		v.strict = d.opts.Strict
+	} else {
+		v.t = TypeArray
+		v.a = v.a[:0]
+		v.depth = depth
+		v.lazy = d
+	}
+	return v
+}
+
+// decode decodes the elements of the lazy array v. The walker starts
+// at the array so that the path of the errors is relative to it.
+func (d *lazyDecoder) decode(v *Value) error {
+	d.w.reset(v.enc, v.i, v.depth, d.opts)
+	if _, _, _, _, err := d.w.next(); err != nil {
+		return err
+	}
+
+	v.a = v.a[:0]
+	for {
+		// the elements of the nested arrays are decoded on first access
+		ev, pos, hdr, size, err := d.w.nextSkip()
+		if err != nil {
+			return err
+		}
+		if ev == walkLeave {
+			break
+		}
+		end := pos + hdr + size
+		elem := d.newValue(ev, v.enc[pos:end:end], v.i+pos, hdr, v.depth+1)
+		if ev == walkEnter {
+			elem.parent = v
+		}
+		v.a = append(v.a, elem)
+	}
+
+	v.lazy = nil
+	return nil
+}
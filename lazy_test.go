@@ -0,0 +1,128 @@
+package fastrlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseLazy(t *testing.T) {
+	p0, p1 := &Parser{}, &Parser{}
+	for i := 0; i < 1000; i++ {
+		buf := generateRandom().MarshalTo(nil)
+
+		v0, err := p0.Parse(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v1, err := p1.ParseLazy(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// marshal before the nested arrays are decoded
+		if !bytes.Equal(v1.MarshalTo(nil), buf) {
+			t.Fatal("bad marshal")
+		}
+		if !sameLazyValue(v0, v1) {
+			t.Fatal("bad")
+		}
+		if !checkRaw(p1, v1) {
+			t.Fatal("bad raw")
+		}
+	}
+}
+
+// sameLazyValue checks that the lazy value v1 decodes the same as v0
+func sameLazyValue(v0, v1 *Value) bool {
+	if v0.t != v1.t || v0.l != v1.l || v0.i != v1.i {
+		return false
+	}
+	if v0.t == TypeBytes {
+		return bytes.Equal(v0.content(), v1.content())
+	}
+	elems, err := v1.GetElems()
+	if err != nil {
+		return false
+	}
+	if len(v0.a) != len(elems) {
+		return false
+	}
+	for i := range v0.a {
+		if !sameLazyValue(v0.a[i], elems[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseLazyErrors(t *testing.T) {
+	// the third element is an array with an element that overflows
+	buf, err := hex.DecodeString("c80183646f67c20182")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &Parser{}
+	if _, err := p.Parse(buf); err == nil {
+		t.Fatal("it should fail")
+	}
+
+	v, err := p.ParseLazy(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, err := v.Get(1).GetString(); err != nil || s != "dog" {
+		t.Fatalf("bad string %s %v", s, err)
+	}
+
+	_, err = v.Get(2).GetElems()
+	if !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected truncated error but found %v", err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatal("expected a parse error")
+	}
+	if perr.Offset != 8 {
+		t.Fatalf("expected offset 8 but found %d", perr.Offset)
+	}
+	// the path is relative to the array being decoded
+	if !reflect.DeepEqual(perr.Path, []int{1}) {
+		t.Fatalf("expected path [1] but found %v", perr.Path)
+	}
+	if v.Get(2).Get(0) != nil {
+		t.Fatal("expected no value")
+	}
+}
+
+func TestParseLazyLimits(t *testing.T) {
+	buf, err := hex.DecodeString("c3c2c101")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := NewParser(ParserOptions{MaxDepth: 2})
+	v, err := p.ParseLazy(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := v.Get(0).GetElems(); !errors.Is(err, ErrMaxDepth) {
+		t.Fatalf("expected depth error but found %v", err)
+	}
+}
+
+func BenchmarkParseLazy(b *testing.B) {
+	buf := benchmarkParserInput()
+	p := &Parser{}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseLazy(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package fastrlp
+
+// Insert inserts vv at index i in the array. i must be in [0, v.Elems()].
+func (v *Value) Insert(i int, vv *Value) {
+	if !v.isMutable() || i < 0 || i > len(v.a) {
+		return
+	}
+	v.attach(vv)
+	v.a = append(v.a, nil)
+	copy(v.a[i+1:], v.a[i:])
+	v.a[i] = vv
+	v.resize(int64(vv.fullLen()))
+}
+
+// Del removes the element at index i from the array.
+func (v *Value) Del(i int) {
+	if !v.isMutable() || i < 0 || i >= len(v.a) {
+		return
+	}
+	vv := v.a[i]
+	v.detach(vv)
+	copy(v.a[i:], v.a[i+1:])
+	v.a[len(v.a)-1] = nil
+	v.a = v.a[:len(v.a)-1]
+	v.resize(-int64(vv.fullLen()))
+}
+
+// Replace replaces the element at index i of the array with vv.
+func (v *Value) Replace(i int, vv *Value) {
+	if !v.isMutable() || i < 0 || i >= len(v.a) {
+		return
+	}
+	old := v.a[i]
+	v.detach(old)
+	v.attach(vv)
+	v.a[i] = vv
+	v.resize(int64(vv.fullLen()) - int64(old.fullLen()))
+}
+
+// Truncate removes all the elements of the array after the first n.
+func (v *Value) Truncate(n int) {
+	if !v.isMutable() || n < 0 || n >= len(v.a) {
+		return
+	}
+	var size uint64
+	for i, vv := range v.a[n:] {
+		v.detach(vv)
+		size += vv.fullLen()
+		v.a[n+i] = nil
+	}
+	v.a = v.a[:n]
+	v.resize(-int64(size))
+}
+
+// isMutable returns true if v is an array with its elements decoded
+func (v *Value) isMutable() bool {
+	if v == nil || v.t != TypeArray {
+		return false
+	}
+	return v.decode() == nil
+}
+
+// attach sets v as the parent of vv
+func (v *Value) attach(vv *Value) {
+	if vv.t == TypeArray {
+		vv.parent = v
+	}
+}
+
+// detach removes v as the parent of vv
+func (v *Value) detach(vv *Value) {
+	if vv.parent == v {
+		vv.parent = nil
+	}
+}
+
+// resize updates the length of the array v and its ancestors
+// after the size of its content changed by delta bytes
+func (v *Value) resize(delta int64) {
+	for ; v != nil; v = v.parent {
+		old := v.fullLen()
+		v.l = uint64(int64(v.l) + delta)
+		// the encoding may reference the input of a parser
+		v.enc = nil
+		delta = int64(v.fullLen()) - int64(old)
+	}
+}
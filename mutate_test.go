@@ -0,0 +1,158 @@
+package fastrlp
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+)
+
+// encodeSlow encodes v computing the length of the arrays from the elements
+func encodeSlow(v *Value) []byte {
+	if v.Type() == TypeBytes {
+		return v.MarshalTo(nil)
+	}
+	var content []byte
+	for i := 0; i < v.Elems(); i++ {
+		content = append(content, encodeSlow(v.Get(i))...)
+	}
+	a := &Arena{}
+	hdr := a.NewBytes(content).MarshalTo(nil)
+	hdr = hdr[:len(hdr)-len(content)]
+	if len(content) == 1 && content[0] <= 0x7F {
+		// single bytes are not prefixed
+		hdr = []byte{0x81}
+	}
+	hdr[0] += 0xC0 - 0x80
+	return append(hdr, content...)
+}
+
+// randomArray returns a random array in the tree of v
+func randomArray(v *Value) *Value {
+	for {
+		var arrays []*Value
+		for i := 0; i < v.Elems(); i++ {
+			if v.Get(i).Type() == TypeArray {
+				arrays = append(arrays, v.Get(i))
+			}
+		}
+		if len(arrays) == 0 || rand.Intn(3) == 0 {
+			return v
+		}
+		v = arrays[rand.Intn(len(arrays))]
+	}
+}
+
+func testMutations(t *testing.T, v *Value, a *Arena) {
+	for i := 0; i < 100; i++ {
+		dst := randomArray(v)
+		elems := dst.Elems()
+
+		switch rand.Intn(5) {
+		case 0:
+			dst.Set(generateRandomImpl(a, 2))
+		case 1:
+			dst.Insert(rand.Intn(elems+1), generateRandomImpl(a, 2))
+		case 2:
+			if elems > 0 {
+				dst.Del(rand.Intn(elems))
+			}
+		case 3:
+			if elems > 0 {
+				dst.Replace(rand.Intn(elems), generateRandomImpl(a, 1))
+			}
+		case 4:
+			dst.Truncate(rand.Intn(elems + 1))
+		}
+
+		if !bytes.Equal(v.MarshalTo(nil), encodeSlow(v)) {
+			t.Fatal("bad encoding")
+		}
+		if !bytes.Equal(v.Encoding(), encodeSlow(v)) {
+			t.Fatal("bad cached encoding")
+		}
+	}
+}
+
+func TestValueMutations(t *testing.T) {
+	t.Run("arena", func(t *testing.T) {
+		a := &Arena{}
+		for i := 0; i < 100; i++ {
+			v := a.NewArray()
+			v.Set(generateRandomImpl(a, 0))
+			testMutations(t, v, a)
+		}
+	})
+
+	t.Run("parsed", func(t *testing.T) {
+		a := &Arena{}
+		p := &Parser{}
+		for i := 0; i < 100; i++ {
+			v := a.NewArray()
+			v.Set(generateRandomImpl(a, 0))
+
+			pv, err := p.Parse(v.MarshalTo(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			testMutations(t, pv, a)
+		}
+	})
+
+	t.Run("lazy", func(t *testing.T) {
+		a := &Arena{}
+		p := &Parser{}
+		for i := 0; i < 100; i++ {
+			v := a.NewArray()
+			v.Set(generateRandomImpl(a, 0))
+
+			pv, err := p.ParseLazy(v.MarshalTo(nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			testMutations(t, pv, a)
+		}
+	})
+}
+
+func TestValueMutationsRaw(t *testing.T) {
+	p := &Parser{}
+	v, err := p.Parse([]byte{0xC2, 0x01, 0x02})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v.Del(0)
+	if raw := p.Raw(v); !bytes.Equal(raw, []byte{0xC1, 0x02}) {
+		t.Fatalf("bad raw after del: %x", raw)
+	}
+
+	// the value grows larger than the input
+	a := &Arena{}
+	v.Set(a.NewBytes(make([]byte, 100)))
+	if raw := p.Raw(v); !bytes.Equal(raw, encodeSlow(v)) {
+		t.Fatalf("bad raw after set: %x", raw)
+	}
+	if err := Validate(p.Raw(v)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValueMutationsLongSize(t *testing.T) {
+	// the size of the header of the ancestors changes
+	a := &Arena{}
+	v := a.NewArray()
+	vv := a.NewArray()
+	v.Set(vv)
+	vv.Set(a.NewBytes(make([]byte, 50)))
+	if !bytes.Equal(v.MarshalTo(nil), encodeSlow(v)) {
+		t.Fatal("bad encoding")
+	}
+	vv.Set(a.NewBytes(make([]byte, 300)))
+	if !bytes.Equal(v.MarshalTo(nil), encodeSlow(v)) {
+		t.Fatal("bad encoding")
+	}
+	vv.Truncate(0)
+	if !bytes.Equal(v.MarshalTo(nil), []byte{0xC1, 0xC0}) {
+		t.Fatal("bad encoding")
+	}
+}
@@ -0,0 +1,283 @@
+package fastrlp
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// String returns the value in the text notation, i.e. ["0x01", ["cat", "dog"]].
+// Bytes values are printed as quoted strings if they only have printable
+// ASCII characters, or as hex strings with the 0x prefix otherwise.
+func (v *Value) String() string {
+	if v == nil {
+		return "<nil>"
+	}
+	return string(v.appendNotation(nil))
+}
+
+// Format implements fmt.Formatter. The %v and %s verbs print the value
+// in the text notation. The %+v verb prints each value in its own line
+// followed by its offset in the encoding and its type.
+func (v *Value) Format(f fmt.State, verb rune) {
+	switch {
+	case v == nil:
+		f.Write([]byte("<nil>"))
+	case verb == 'v' && f.Flag('+'):
+		f.Write(v.appendNotationIndent(nil, 0, 0, ""))
+	case verb == 'v' || verb == 's':
+		f.Write(v.appendNotation(nil))
+	default:
+		fmt.Fprintf(f, "%%!%c(*fastrlp.Value=%s)", verb, v.String())
+	}
+}
+
+func (v *Value) appendNotation(dst []byte) []byte {
+	if v.t == TypeBytes {
+		return appendBytesNotation(dst, v.content())
+	}
+	if v.decode() != nil {
+		return appendInvalidNotation(dst, v)
+	}
+	dst = append(dst, '[')
+	for i, vv := range v.a {
+		if i != 0 {
+			dst = append(dst, ", "...)
+		}
+		dst = vv.appendNotation(dst)
+	}
+	return append(dst, ']')
+}
+
+// appendNotationIndent appends v in the indented notation. offset is the
+// position of v in the encoding of the root value and sep is appended
+// after v.
+func (v *Value) appendNotationIndent(dst []byte, indent int, offset uint64, sep string) []byte {
+	if v.t == TypeBytes {
+		dst = appendBytesNotation(dst, v.content())
+		dst = append(dst, sep...)
+		return appendNotationComment(dst, offset, v.t)
+	}
+	if v.decode() != nil {
+		dst = appendInvalidNotation(dst, v)
+		dst = append(dst, sep...)
+		return appendNotationComment(dst, offset, v.t)
+	}
+	if len(v.a) == 0 {
+		dst = append(dst, "[]"...)
+		dst = append(dst, sep...)
+		return appendNotationComment(dst, offset, v.t)
+	}
+
+	dst = append(dst, '[')
+	dst = appendNotationComment(dst, offset, v.t)
+
+	// skip the header of the array
+	offset += v.fullLen() - v.l
+	for i, vv := range v.a {
+		elemSep := ","
+		if i == len(v.a)-1 {
+			elemSep = ""
+		}
+		dst = appendNotationNewline(dst, indent+1)
+		dst = vv.appendNotationIndent(dst, indent+1, offset, elemSep)
+		offset += vv.fullLen()
+	}
+	dst = appendNotationNewline(dst, indent)
+	dst = append(dst, ']')
+	return append(dst, sep...)
+}
+
+func appendNotationNewline(dst []byte, indent int) []byte {
+	dst = append(dst, '\n')
+	for i := 0; i < indent; i++ {
+		dst = append(dst, "  "...)
+	}
+	return dst
+}
+
+func appendNotationComment(dst []byte, offset uint64, t Type) []byte {
+	dst = append(dst, " // offset "...)
+	dst = strconv.AppendUint(dst, offset, 10)
+	dst = append(dst, ", "...)
+	return append(dst, t.String()...)
+}
+
+func appendBytesNotation(dst []byte, b []byte) []byte {
+	if isPrintable(b) {
+		return strconv.AppendQuote(dst, string(b))
+	}
+	dst = append(dst, `"0x`...)
+	dst = appendHex(dst, b)
+	return append(dst, '"')
+}
+
+// appendInvalidNotation appends the encoding of a lazy array with invalid elements
+func appendInvalidNotation(dst []byte, v *Value) []byte {
+	dst = append(dst, "<invalid 0x"...)
+	dst = appendHex(dst, v.Encoding())
+	return append(dst, '>')
+}
+
+func appendHex(dst []byte, b []byte) []byte {
+	for _, c := range b {
+		dst = append(dst, hextable[c>>4], hextable[c&0x0f])
+	}
+	return dst
+}
+
+// isPrintable returns true if b only has printable ASCII characters
+// and it cannot be confused with a hex string.
+func isPrintable(b []byte) bool {
+	if len(b) >= 2 && b[0] == '0' && b[1] == 'x' {
+		return false
+	}
+	for _, c := range b {
+		if c < 0x20 || c > 0x7E {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseNotation parses a value in the text notation and builds it in a new
+// Arena. Bytes values are written either as quoted strings, as quoted hex
+// strings with the 0x prefix or as unsigned decimal integers, and arrays as
+// comma separated lists between brackets:
+//
+//	["0x01", ["cat", "dog"], 300]
+//
+// The text after // until the end of the line is ignored, which means that
+// the output of both %v and %+v can be parsed back.
+func ParseNotation(s string) (*Value, error) {
+	n := &notationParser{s: s, a: &Arena{}}
+	v, err := n.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	n.skipSpace()
+	if n.pos != len(n.s) {
+		return nil, n.errorf("unexpected %q", n.s[n.pos])
+	}
+	return v, nil
+}
+
+// notationParser parses the text notation of a value
+type notationParser struct {
+	s   string
+	pos int
+	a   *Arena
+}
+
+func (n *notationParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d", ErrBadNotation, fmt.Sprintf(format, args...), n.pos)
+}
+
+func (n *notationParser) skipSpace() {
+	for n.pos < len(n.s) {
+		switch c := n.s[n.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			n.pos++
+		case strings.HasPrefix(n.s[n.pos:], "//"):
+			end := strings.IndexByte(n.s[n.pos:], '\n')
+			if end == -1 {
+				n.pos = len(n.s)
+			} else {
+				n.pos += end
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (n *notationParser) parseValue() (*Value, error) {
+	n.skipSpace()
+	if n.pos == len(n.s) {
+		return nil, n.errorf("unexpected end of input")
+	}
+	switch c := n.s[n.pos]; {
+	case c == '[':
+		return n.parseArray()
+	case c == '"':
+		return n.parseString()
+	case c >= '0' && c <= '9':
+		return n.parseInt()
+	default:
+		return nil, n.errorf("unexpected %q", c)
+	}
+}
+
+func (n *notationParser) parseArray() (*Value, error) {
+	// skip the opening bracket
+	n.pos++
+
+	v := n.a.NewArray()
+	n.skipSpace()
+	if n.pos < len(n.s) && n.s[n.pos] == ']' {
+		n.pos++
+		return v, nil
+	}
+	for {
+		elem, err := n.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		v.Set(elem)
+
+		n.skipSpace()
+		if n.pos == len(n.s) {
+			return nil, n.errorf("unexpected end of input")
+		}
+		switch c := n.s[n.pos]; c {
+		case ',':
+			n.pos++
+		case ']':
+			n.pos++
+			return v, nil
+		default:
+			return nil, n.errorf("unexpected %q", c)
+		}
+	}
+}
+
+func (n *notationParser) parseString() (*Value, error) {
+	end := n.pos + 1
+	for ; end < len(n.s) && n.s[end] != '"'; end++ {
+		if n.s[end] == '\\' {
+			end++
+		}
+	}
+	if end >= len(n.s) {
+		return nil, n.errorf("unterminated string")
+	}
+	str, err := strconv.Unquote(n.s[n.pos : end+1])
+	if err != nil {
+		return nil, n.errorf("invalid string: %v", err)
+	}
+	if strings.HasPrefix(str, "0x") {
+		b, err := hex.DecodeString(str[2:])
+		if err != nil {
+			return nil, n.errorf("invalid hex string: %v", err)
+		}
+		n.pos = end + 1
+		return n.a.NewBytes(b), nil
+	}
+	n.pos = end + 1
+	return n.a.NewString(str), nil
+}
+
+func (n *notationParser) parseInt() (*Value, error) {
+	end := n.pos
+	for end < len(n.s) && n.s[end] >= '0' && n.s[end] <= '9' {
+		end++
+	}
+	num, ok := new(big.Int).SetString(n.s[n.pos:end], 10)
+	if !ok {
+		return nil, n.errorf("invalid integer")
+	}
+	n.pos = end
+	return n.a.NewBigInt(num), nil
+}
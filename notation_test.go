@@ -0,0 +1,123 @@
+package fastrlp
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestNotationString(t *testing.T) {
+	a := &Arena{}
+
+	v := a.NewArray()
+	v.Set(a.NewUint(1))
+	vv := a.NewArray()
+	vv.Set(a.NewString("cat"))
+	vv.Set(a.NewString("dog"))
+	v.Set(vv)
+	v.Set(a.NewUint(300))
+	v.Set(a.NewArray())
+	v.Set(a.NewNull())
+
+	expected := `["0x01", ["cat", "dog"], "0x012c", [], ""]`
+	if str := v.String(); str != expected {
+		t.Fatalf("expected %s but found %s", expected, str)
+	}
+	if str := fmt.Sprintf("%v", v); str != expected {
+		t.Fatalf("expected %s but found %s", expected, str)
+	}
+
+	expected = `[ // offset 0, array
+  "0x01", // offset 1, bytes
+  [ // offset 2, array
+    "cat", // offset 3, bytes
+    "dog" // offset 7, bytes
+  ],
+  "0x012c", // offset 11, bytes
+  [], // offset 14, array
+  "" // offset 15, bytes
+]`
+	if str := fmt.Sprintf("%+v", v); str != expected {
+		t.Fatalf("expected %s but found %s", expected, str)
+	}
+
+	// printable strings that look like hex
+	if str := a.NewString("0xcat").String(); str != `"0x3078636174"` {
+		t.Fatalf("bad: %s", str)
+	}
+}
+
+func TestNotationParse(t *testing.T) {
+	cases := []struct {
+		notation string
+		enc      string
+	}{
+		{`""`, "80"},
+		{`"0x"`, "80"},
+		{`0`, "80"},
+		{`"0x00"`, "00"},
+		{`127`, "7f"},
+		{`128`, "8180"},
+		{`1000`, "8203e8"},
+		{`"dog"`, "83646f67"},
+		{`[]`, "c0"},
+		{`["dog", "god", "cat"]`, "cc83646f6783676f6483636174"},
+		{`["zw", [4], 1]`, "c6827a77c10401"},
+		{`[[], [[]], [[], [[]]]]`, "c7c0c1c0c3c0c1c0"},
+		{"[\n  \"0x01\", // comment\n  [\"cat\"]\n]", "c601c483636174"},
+		{`18446744073709551616`, "89010000000000000000"},
+		{`"a\"b"`, "83612262"},
+	}
+	for _, c := range cases {
+		v, err := ParseNotation(c.notation)
+		if err != nil {
+			t.Fatalf("%s: %v", c.notation, err)
+		}
+		if enc := hex.EncodeToString(v.MarshalTo(nil)); enc != c.enc {
+			t.Fatalf("%s: expected %s but found %s", c.notation, c.enc, enc)
+		}
+	}
+}
+
+func TestNotationParseError(t *testing.T) {
+	cases := []string{
+		``,
+		`[`,
+		`["a"`,
+		`["a",]`,
+		`["a" "b"]`,
+		`"abc`,
+		`"0x0"`,
+		`"0xzz"`,
+		`-1`,
+		`[] []`,
+		`abc`,
+	}
+	for _, c := range cases {
+		if _, err := ParseNotation(c); !errors.Is(err, ErrBadNotation) {
+			t.Fatalf("%s: expected bad notation but found %v", c, err)
+		}
+	}
+}
+
+func TestNotationRoundTrip(t *testing.T) {
+	p := &Parser{}
+	for i := 0; i < 100; i++ {
+		v := generateRandom()
+
+		pv, err := p.Parse(v.MarshalTo(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, format := range []string{"%v", "%+v"} {
+			vv, err := ParseNotation(fmt.Sprintf(format, pv))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !vv.Equal(pv) {
+				t.Fatal("bad")
+			}
+		}
+	}
+}
@@ -2,145 +2,250 @@ package fastrlp
 
 import (
 	"encoding/binary"
-	"fmt"
+	"math"
 )
 
+// maxValueSize is the maximum size of a value, which must fit in a slice
+const maxValueSize = math.MaxInt
+
+// ParserOptions are the limits enforced by the Parser while decoding.
+// A zero value for any of the fields means no limit.
+type ParserOptions struct {
+	// MaxDepth is the maximum nesting level of lists
+	MaxDepth int
+
+	// MaxListElems is the maximum number of elements in a single list
+	MaxListElems int
+
+	// MaxBytesLen is the maximum length of a single bytes value
+	MaxBytesLen uint64
+
+	// MaxInputSize is the maximum size of the input buffer
+	MaxInputSize uint64
+
+	// Strict makes the integer getters of the parsed values reject
+	// non-canonical integers and GetBool reject values other than 0 and 1
+	Strict bool
+}
+
 // Parser is a RLP parser
 type Parser struct {
-	buf []byte
-	c   cache
-	k   *Keccak
+	buf  []byte
+	c    cache
+	k    *Keccak
+	opts ParserOptions
+	w    walker
+	lazy lazyDecoder
+
+	// stack are the arrays being parsed
+	stack []*Value
+
+	// tape is the result of ParseTape and tstack are the
+	// indexes in the tape of the arrays being parsed
+	tape   Tape
+	tstack []int
+}
+
+// NewParser returns a Parser that enforces the given options.
+func NewParser(opts ParserOptions) *Parser {
+	return &Parser{opts: opts}
+}
+
+// SetOptions sets the limits enforced by the parser.
+func (p *Parser) SetOptions(opts ParserOptions) {
+	p.opts = opts
 }
 
 // Parse parses a complete rlp encoding
 func (p *Parser) Parse(b []byte) (*Value, error) {
-	p.c.reset()
+	if err := p.checkInputSize(len(b)); err != nil {
+		return nil, err
+	}
+
 	p.buf = append(p.buf[:0], b...)
+	return p.parseBuf()
+}
+
+// parseBuf parses the complete encoding in the parser buffer
+func (p *Parser) parseBuf() (*Value, error) {
+	p.c.reset()
 
-	v, _, err := parseValue(p.buf, &p.c)
+	v, tail, err := p.parseValue(p.buf)
 	if err != nil {
-		return nil, fmt.Errorf("cannot parse RLP: %s", err)
+		return nil, err
+	}
+	if len(tail) != 0 {
+		return nil, &ParseError{Offset: p.c.indx, Kind: ErrTrailingData}
 	}
 	return v, nil
 }
 
-// Raw returns the raw bytes of the value
+func (p *Parser) checkInputSize(size int) error {
+	if p.opts.MaxInputSize != 0 && uint64(size) > p.opts.MaxInputSize {
+		return newLimitError(ErrMaxInputSize, p.opts.MaxInputSize)
+	}
+	return nil
+}
+
+// Raw returns the raw bytes of the value. It is the same as v.Encoding
+// and it is valid after the value is modified.
 func (p *Parser) Raw(v *Value) []byte {
-	return p.buf[v.i : v.i+v.fullLen()]
+	return v.Encoding()
 }
 
-// Hash performs a keccak hash of the rlp value
+// Hash performs a keccak hash of the rlp value. The value
+// may be parsed by any parser or built in an arena.
 func (p *Parser) Hash(dst []byte, v *Value) []byte {
 	if p.k == nil {
 		p.k = NewKeccak256()
 	}
 	p.k.Reset()
-	p.k.Write(p.Raw(v))
+	p.k.Write(v.Encoding())
 	return p.k.Sum(dst)
 }
 
-func parseValue(b []byte, c *cache) (*Value, []byte, error) {
+// readHeader reads the header of the value at the start of b. It returns
+// the type of the value, the size of the header and the size of the content.
+// The returned error is one of the sentinel errors.
+func readHeader(b []byte) (Type, uint64, uint64, error) {
+	t, hdr, size, err := readSize(b)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	if hdr == 1 && size == 1 && t == TypeBytes && len(b) > 1 && b[1] < 0x80 {
+		// single byte encoded as short bytes
+		return 0, 0, 0, ErrNonCanonicalSize
+	}
+	if size > uint64(len(b))-hdr {
+		return 0, 0, 0, ErrTruncated
+	}
+	return t, hdr, size, nil
+}
+
+// checkValueSize returns ErrSizeOverflow if a value with a header of hdr bytes
+// and a content of size bytes is larger than maxValueSize. It does not add
+// both sizes so that it cannot overflow.
+func checkValueSize(hdr, size uint64) error {
+	if size > uint64(maxValueSize)-hdr {
+		return ErrSizeOverflow
+	}
+	return nil
+}
+
+// headerLen returns the size of the header that starts with the byte cur.
+func headerLen(cur byte) uint64 {
+	switch {
+	case cur < 0x80:
+		return 0
+	case cur < 0xB8:
+		return 1
+	case cur < 0xC0:
+		return uint64(cur-0xB7) + 1
+	case cur < 0xF8:
+		return 1
+	default:
+		return uint64(cur-0xF7) + 1
+	}
+}
+
+// readSize is like readHeader but it only reads the header and
+// does not check that b has enough bytes for the content.
+func readSize(b []byte) (Type, uint64, uint64, error) {
 	if len(b) == 0 {
-		return nil, b, fmt.Errorf("cannot parse empty string")
+		return 0, 0, 0, ErrTruncated
 	}
 
 	cur := b[0]
-	if cur < 0x80 {
-		v := c.getValue()
-		v.t = TypeBytes
-		v.b = b[:1]
-		v.l = 1
-		v.i = c.indx
-		c.indx++
-		return v, b[1:], nil
-	}
-	if cur < 0xB8 {
-		v, tail, err := parseBytes(b[1:], 0, uint64(cur-0x80), c)
-		if err != nil {
-			return nil, tail, fmt.Errorf("cannot parse short bytes: %s", err)
-		}
-		if v.l == 1 && v.b[0] < 128 {
-			return nil, nil, fmt.Errorf("bad size")
-		}
-		return v, tail, nil
-	}
-	if cur < 0xC0 {
-		intSize := int(cur - 0xB7)
-		if len(b) < intSize+1 {
-			return nil, nil, fmt.Errorf("bad size")
-		}
-		size := readUint(b[1:intSize+1], c.buf[:])
-		if size < 56 {
-			return nil, nil, fmt.Errorf("bad size")
-		}
-		v, tail, err := parseBytes(b[intSize+1:], uint64(intSize), size, c)
-		if err != nil {
-			return nil, tail, fmt.Errorf("cannot parse long bytes: %s", err)
-		}
-		return v, tail, nil
+	hdr := headerLen(cur)
+
+	t := TypeBytes
+	if cur >= 0xC0 {
+		t = TypeArray
 	}
-	if cur < 0xF8 {
-		v, tail, err := parseList(b[1:], 0, int(cur-0xC0), c)
-		if err != nil {
-			return nil, tail, fmt.Errorf("cannot parse short bytes: %s", err)
+
+	switch {
+	case hdr == 0:
+		// single byte
+		return TypeBytes, 0, 1, nil
+
+	case hdr == 1:
+		// short bytes or array
+		if t == TypeBytes {
+			return t, 1, uint64(cur - 0x80), nil
 		}
-		return v, tail, nil
+		return t, 1, uint64(cur - 0xC0), nil
 	}
 
-	intSize := int(cur - 0xF7)
-	if len(b) < intSize+1 {
-		return nil, nil, fmt.Errorf("bad size")
+	// long bytes or array
+	if uint64(len(b)) < hdr {
+		return 0, 0, 0, ErrTruncated
 	}
-	size := readUint(b[1:intSize+1], c.buf[:])
-	if size < 56 {
-		return nil, nil, fmt.Errorf("bad size")
+	if b[1] == 0 {
+		// leading zeros in the size
+		return 0, 0, 0, ErrNonCanonicalSize
 	}
-	v, tail, err := parseList(b[intSize+1:], intSize, int(size), c)
-	if err != nil {
-		return nil, tail, fmt.Errorf("cannot parse long array: %s", err)
+	var buf [8]byte
+	size := readUint(b[1:hdr], buf[:])
+	if size < 56 {
+		return 0, 0, 0, ErrNonCanonicalSize
 	}
-	return v, tail, nil
+	return t, hdr, size, nil
 }
 
-func parseBytes(b []byte, bytes uint64, size uint64, c *cache) (*Value, []byte, error) {
-	if size > uint64(len(b)) {
-		return nil, nil, fmt.Errorf("length is not enough")
-	}
+// parseValue parses the value at the start of b. The offset of b in
+// the parser buffer is c.indx. It returns the value and the remaining bytes.
+func (p *Parser) parseValue(b []byte) (*Value, []byte, error) {
+	c := &p.c
+	base := c.indx
+	p.w.reset(b, base, 0, p.opts)
+	p.stack = p.stack[:0]
 
-	v := c.getValue()
-	v.t = TypeBytes
-	v.b = b[:size]
-	v.l = uint64(size)
-	v.i = c.indx
+	for {
+		ev, pos, hdr, size, err := p.w.next()
+		if err != nil {
+			return nil, nil, err
+		}
 
-	c.indx += bytes + size + 1
-	return v, b[size:], nil
-}
+		var v *Value
+		if ev == walkLeave {
+			v = p.stack[len(p.stack)-1]
+			p.stack = p.stack[:len(p.stack)-1]
+		} else {
+			v = c.getValue()
+			v.i = base + pos
+			v.l = size
+			v.lazy = nil
+			v.parent = nil
+			v.enc = b[pos : pos+hdr+size : pos+hdr+size]
+			v.hdr = uint8(hdr)
 
-func parseList(b []byte, bytes int, size int, c *cache) (*Value, []byte, error) {
-	a := c.getValue()
-	a.t = TypeArray
-	a.a = a.a[:0]
-	a.l = uint64(size)
-	a.i = c.indx
+			if ev == walkBytes {
+				v.t = TypeBytes
+				v.strict = p.opts.Strict
+			} else {
+				v.t = TypeArray
+				v.a = v.a[:0]
+			}
 
-	var v *Value
-	var err error
+			if len(p.stack) != 0 {
+				top := p.stack[len(p.stack)-1]
+				top.a = append(top.a, v)
+				if ev == walkEnter {
+					v.parent = top
+				}
+			}
+			if ev == walkEnter {
+				// parse the elements of the list
+				p.stack = append(p.stack, v)
+				continue
+			}
+		}
 
-	c.indx += uint64(bytes) + 1
-	for size > 0 {
-		pre := len(b)
-		v, b, err = parseValue(b, c)
-		if err != nil {
-			return nil, b, fmt.Errorf("cannot parse array value: %s", err)
+		if len(p.stack) == 0 {
+			c.indx = base + p.w.pos
+			return v, b[p.w.pos:], nil
 		}
-		a.a = append(a.a, v)
-		size -= pre - len(b)
-	}
-	if size < 0 {
-		return nil, nil, fmt.Errorf("bad ending")
 	}
-	return a, b, nil
 }
 
 func readUint(b []byte, buf []byte) uint64 {
@@ -3,7 +3,12 @@ package fastrlp
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
 	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -23,29 +28,23 @@ func TestEncodingRawRandom(t *testing.T) {
 		if !bytes.Equal(buf, buf1) {
 			t.Fatal("bad")
 		}
-		if !checkRaw(p, v, v0) {
+		if !checkRaw(p, v) {
 			t.Fatal("bad")
 		}
 	}
 }
 
-func checkRaw(p *Parser, v *Value, v0 *Value) bool {
-	if v.Type() == TypeArray {
-		elems, err := v.GetElems()
-		if err != nil {
-			panic(err)
-		}
-		for indx, elem := range elems {
-			if !checkRaw(p, elem, v0.Get(indx)) {
-				return false
-			}
+func checkRaw(p *Parser, v *Value) bool {
+	ok := true
+	err := v.Walk(func(path []int, v *Value) WalkAction {
+		buf := p.Raw(v)
+		if !bytes.Equal(buf, v.MarshalTo(nil)) || !bytes.Equal(buf, v.Encoding()) {
+			ok = false
+			return WalkStop
 		}
-	}
-	buf := p.Raw(v)
-	if !bytes.Equal(buf, v.MarshalTo(nil)) {
-		return false
-	}
-	return true
+		return WalkContinue
+	})
+	return ok && err == nil
 }
 
 func randomInt(min, max int) int {
@@ -85,6 +84,9 @@ func TestInvalidRlp(t *testing.T) {
 		"8100",
 		"8101",
 		"817F",
+		// trailing bytes
+		"01ffff",
+		"c10102",
 	}
 
 	p := &Parser{}
@@ -99,3 +101,614 @@ func TestInvalidRlp(t *testing.T) {
 		}
 	}
 }
+
+func TestParserLimits(t *testing.T) {
+	cases := []struct {
+		input string
+		opts  ParserOptions
+		err   error
+	}{
+		{
+			// [[[]]]
+			"c2c1c0",
+			ParserOptions{MaxDepth: 2},
+			ErrMaxDepth,
+		},
+		{
+			"c2c1c0",
+			ParserOptions{MaxDepth: 3},
+			nil,
+		},
+		{
+			// [1, 2, 3]
+			"c3010203",
+			ParserOptions{MaxListElems: 2},
+			ErrMaxListElems,
+		},
+		{
+			"c3010203",
+			ParserOptions{MaxListElems: 3},
+			nil,
+		},
+		{
+			// "dog"
+			"83646f67",
+			ParserOptions{MaxBytesLen: 2},
+			ErrMaxBytesLen,
+		},
+		{
+			"83646f67",
+			ParserOptions{MaxBytesLen: 3},
+			nil,
+		},
+		{
+			"83646f67",
+			ParserOptions{MaxInputSize: 3},
+			ErrMaxInputSize,
+		},
+		{
+			"83646f67",
+			ParserOptions{MaxInputSize: 4},
+			nil,
+		},
+	}
+
+	for _, c := range cases {
+		buf, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		p := NewParser(c.opts)
+		_, err = p.Parse(buf)
+		if c.err == nil {
+			if err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if !errors.Is(err, c.err) {
+			t.Fatalf("expected %v but found %v", c.err, err)
+		}
+		var limitErr *LimitError
+		if !errors.As(err, &limitErr) {
+			t.Fatal("expected a limit error")
+		}
+	}
+}
+
+func TestParserHugeListSize(t *testing.T) {
+	// list with an 8 byte size prefix larger than the input
+	buf, err := hex.DecodeString("ffffffffffffffffff01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &Parser{}
+	if _, err := p.Parse(buf); err == nil {
+		t.Fatal("it should fail")
+	}
+}
+
+func TestParseError(t *testing.T) {
+	cases := []struct {
+		input  string
+		kind   error
+		offset uint64
+		path   []int
+	}{
+		{
+			"",
+			ErrTruncated,
+			0,
+			nil,
+		},
+		{
+			// short bytes without enough content
+			"83646f",
+			ErrTruncated,
+			0,
+			nil,
+		},
+		{
+			// single byte encoded as short bytes
+			"c3018101",
+			ErrNonCanonicalSize,
+			2,
+			[]int{1},
+		},
+		{
+			// long bytes with a size lower than 56
+			"c4c3b80101",
+			ErrNonCanonicalSize,
+			2,
+			[]int{0, 0},
+		},
+		{
+			// long bytes with leading zeros in the size
+			"b900" + strings.Repeat("01", 256),
+			ErrNonCanonicalSize,
+			0,
+			nil,
+		},
+		{
+			// the second element overflows the list
+			"c30182646f",
+			ErrElemTooLarge,
+			2,
+			[]int{1},
+		},
+		{
+			"01ffff",
+			ErrTrailingData,
+			1,
+			nil,
+		},
+	}
+
+	p := &Parser{}
+	for _, c := range cases {
+		buf, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = p.Parse(buf)
+		if !errors.Is(err, c.kind) {
+			t.Fatalf("%s: expected %v but found %v", c.input, c.kind, err)
+		}
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%s: expected a parse error", c.input)
+		}
+		if perr.Offset != c.offset {
+			t.Fatalf("%s: expected offset %d but found %d", c.input, c.offset, perr.Offset)
+		}
+		if !reflect.DeepEqual(perr.Path, c.path) {
+			t.Fatalf("%s: expected path %v but found %v", c.input, c.path, perr.Path)
+		}
+	}
+}
+
+func TestValueGetterErrors(t *testing.T) {
+	a := &Arena{}
+
+	if _, err := a.NewArray().GetUint64(); !errors.Is(err, ErrExpectedBytes) {
+		t.Fatalf("expected bytes error but found %v", err)
+	}
+	if _, err := a.NewString("dog").GetElems(); !errors.Is(err, ErrExpectedList) {
+		t.Fatalf("expected list error but found %v", err)
+	}
+	if err := a.NewString("dog").GetAddr(nil); !errors.Is(err, ErrBadLength) {
+		t.Fatalf("expected length error but found %v", err)
+	}
+	if _, err := a.NewBytes(make([]byte, 9)).GetUint64(); !errors.Is(err, ErrUint64Range) {
+		t.Fatalf("expected range error but found %v", err)
+	}
+	if _, err := a.NewUint(2).GetBool(); !errors.Is(err, ErrInvalidBool) {
+		t.Fatalf("expected bool error but found %v", err)
+	}
+}
+
+func TestParserStrictIntegers(t *testing.T) {
+	cases := []struct {
+		input string
+		err   error
+	}{
+		{"80", nil},
+		{"01", nil},
+		{"820100", nil},
+		{"00", ErrCanonZero},
+		{"820001", ErrCanonInt},
+		{"8900ffffffffffffffff", ErrCanonInt},
+	}
+
+	for _, c := range cases {
+		buf, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the default parser accepts non-canonical integers
+		p := &Parser{}
+		v, err := p.Parse(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := v.GetBigInt(new(big.Int)); err != nil {
+			t.Fatal(err)
+		}
+
+		p = NewParser(ParserOptions{Strict: true})
+		if v, err = p.Parse(buf); err != nil {
+			t.Fatal(err)
+		}
+		if err := v.GetBigInt(new(big.Int)); !errors.Is(err, c.err) {
+			t.Fatalf("%s: expected %v but found %v", c.input, c.err, err)
+		}
+		if len(v.content()) <= 8 {
+			if _, err := v.GetUint64(); !errors.Is(err, c.err) {
+				t.Fatalf("%s: expected %v but found %v", c.input, c.err, err)
+			}
+		}
+	}
+}
+
+func TestParserStrictBool(t *testing.T) {
+	cases := []struct {
+		input string
+		res   bool
+		err   error
+	}{
+		{"80", false, nil},
+		{"01", true, nil},
+		{"00", false, ErrCanonZero},
+		{"820001", false, ErrCanonInt},
+		{"02", false, ErrInvalidBool},
+	}
+
+	p := NewParser(ParserOptions{Strict: true})
+	for _, c := range cases {
+		buf, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := p.Parse(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := v.GetBool()
+		if !errors.Is(err, c.err) {
+			t.Fatalf("%s: expected %v but found %v", c.input, c.err, err)
+		}
+		if res != c.res {
+			t.Fatalf("%s: expected %v but found %v", c.input, c.res, res)
+		}
+	}
+}
+
+func TestParserIterativeMatchesRecursive(t *testing.T) {
+	p0, p1 := &Parser{}, &Parser{}
+	for i := 0; i < 1000; i++ {
+		buf := generateRandom().MarshalTo(nil)
+
+		v0, err := p0.parseRecursive(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v1, err := p1.Parse(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !sameValue(v0, v1) {
+			t.Fatal("bad")
+		}
+
+		// corrupt the input and check that both return the same error
+		buf[rand.Intn(len(buf))] ^= byte(1 + rand.Intn(255))
+		_, err0 := p0.parseRecursive(buf)
+		_, err1 := p1.Parse(buf)
+		if !reflect.DeepEqual(err0, err1) {
+			t.Fatalf("expected '%v' but found '%v'", err0, err1)
+		}
+	}
+}
+
+// sameValue checks that both values have the same content and offsets
+func sameValue(v0, v1 *Value) bool {
+	if v0.t != v1.t || v0.l != v1.l || v0.i != v1.i {
+		return false
+	}
+	if v0.t == TypeBytes {
+		return bytes.Equal(v0.content(), v1.content())
+	}
+	if len(v0.a) != len(v1.a) {
+		return false
+	}
+	for i := range v0.a {
+		if !sameValue(v0.a[i], v1.a[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func benchmarkDeepInput() []byte {
+	a := &Arena{}
+	v := a.NewArray()
+	for i := 0; i < 1000; i++ {
+		vv := a.NewArray()
+		vv.Set(v)
+		v = vv
+	}
+	return v.MarshalTo(nil)
+}
+
+func benchmarkParserInput() []byte {
+	a := &Arena{}
+	v := a.NewArray()
+	for i := 0; i < 100; i++ {
+		// nested list similar to a block header
+		vv := a.NewArray()
+		for j := 0; j < 15; j++ {
+			vv.Set(a.NewUint(uint64(i * j)))
+			vv.Set(a.NewBytes(make([]byte, 32)))
+		}
+		v.Set(vv)
+	}
+	return v.MarshalTo(nil)
+}
+
+func BenchmarkParse(b *testing.B) {
+	buf := benchmarkParserInput()
+	p := &Parser{}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Parse(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseBaseline(b *testing.B) {
+	buf := benchmarkParserInput()
+	p := &baselineParser{}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		if _, err := p.parse(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseDeep(b *testing.B) {
+	buf := benchmarkDeepInput()
+	p := &Parser{}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.Parse(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseDeepBaseline(b *testing.B) {
+	buf := benchmarkDeepInput()
+	p := &baselineParser{}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := p.parse(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// withIndex prepends the index of the element in its list to the path of the error
+func withIndex(err error, indx int) error {
+	if perr, ok := err.(*ParseError); ok {
+		perr.Path = append([]int{indx}, perr.Path...)
+	}
+	return err
+}
+
+// parseRecursive is the recursive implementation of the parser
+// used as a reference for the iterative one
+func (p *Parser) parseRecursive(b []byte) (*Value, error) {
+	p.c.reset()
+	p.buf = append(p.buf[:0], b...)
+
+	v, tail, err := p.parseValueRecursive(p.buf, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(tail) != 0 {
+		return nil, &ParseError{Offset: p.c.indx, Kind: ErrTrailingData}
+	}
+	return v, nil
+}
+
+func (p *Parser) parseValueRecursive(b []byte, depth int) (*Value, []byte, error) {
+	c := &p.c
+
+	t, hdr, size, err := readHeader(b)
+	if err != nil {
+		return nil, nil, &ParseError{Offset: c.indx, Kind: err}
+	}
+	if t == TypeBytes {
+		if p.opts.MaxBytesLen != 0 && size > p.opts.MaxBytesLen {
+			err := newLimitError(ErrMaxBytesLen, p.opts.MaxBytesLen)
+			err.Offset = c.indx
+			return nil, nil, err
+		}
+
+		v := c.getValue()
+		v.t = TypeBytes
+		v.enc = b[:hdr+size]
+		v.hdr = uint8(hdr)
+		v.l = size
+		v.i = c.indx
+		v.strict = p.opts.Strict
+
+		c.indx += hdr + size
+		return v, b[hdr+size:], nil
+	}
+
+	if p.opts.MaxDepth != 0 && depth >= p.opts.MaxDepth {
+		err := newLimitError(ErrMaxDepth, uint64(p.opts.MaxDepth))
+		err.Offset = c.indx
+		return nil, nil, err
+	}
+	return p.parseListRecursive(b[hdr:], hdr, size, depth+1)
+}
+
+func (p *Parser) parseListRecursive(b []byte, hdr uint64, size uint64, depth int) (*Value, []byte, error) {
+	c := &p.c
+	a := c.getValue()
+	a.t = TypeArray
+	a.a = a.a[:0]
+	a.l = size
+	a.i = c.indx
+
+	var v *Value
+	var err error
+
+	c.indx += hdr
+	for size > 0 {
+		indx := len(a.a)
+		if p.opts.MaxListElems != 0 && indx >= p.opts.MaxListElems {
+			err := newLimitError(ErrMaxListElems, uint64(p.opts.MaxListElems))
+			err.Offset = c.indx
+			return nil, nil, withIndex(err, indx)
+		}
+		pre, offset := len(b), c.indx
+		v, b, err = p.parseValueRecursive(b, depth)
+		if err != nil {
+			return nil, nil, withIndex(err, indx)
+		}
+		a.a = append(a.a, v)
+
+		read := uint64(pre - len(b))
+		if read > size {
+			return nil, nil, withIndex(&ParseError{Offset: offset, Kind: ErrElemTooLarge}, indx)
+		}
+		size -= read
+	}
+	return a, b, nil
+}
+
+// baselineValue has the fields of the Value before the parser limits,
+// lazy decoding, encodings and mutations were added
+type baselineValue struct {
+	t Type
+	a []*baselineValue
+	b []byte
+	l uint64
+	i uint64
+}
+
+// baselineParser is the original recursive parser, without limits and with
+// plain text errors. It is only used as a reference in the benchmarks.
+type baselineParser struct {
+	buf  []byte
+	vs   []baselineValue
+	indx uint64
+	tmp  [8]byte
+}
+
+func (p *baselineParser) getValue() *baselineValue {
+	if cap(p.vs) > len(p.vs) {
+		p.vs = p.vs[:len(p.vs)+1]
+	} else {
+		p.vs = append(p.vs, baselineValue{})
+	}
+	return &p.vs[len(p.vs)-1]
+}
+
+func (p *baselineParser) parse(b []byte) (*baselineValue, error) {
+	p.vs = p.vs[:0]
+	p.indx = 0
+	p.buf = append(p.buf[:0], b...)
+
+	v, _, err := p.parseValue(p.buf)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse RLP: %s", err)
+	}
+	return v, nil
+}
+
+func (p *baselineParser) parseValue(b []byte) (*baselineValue, []byte, error) {
+	if len(b) == 0 {
+		return nil, b, fmt.Errorf("cannot parse empty string")
+	}
+
+	cur := b[0]
+	if cur < 0x80 {
+		v := p.getValue()
+		v.t = TypeBytes
+		v.b = b[:1]
+		v.l = 1
+		v.i = p.indx
+		p.indx++
+		return v, b[1:], nil
+	}
+	if cur < 0xB8 {
+		v, tail, err := p.parseBytes(b[1:], 0, uint64(cur-0x80))
+		if err != nil {
+			return nil, tail, fmt.Errorf("cannot parse short bytes: %s", err)
+		}
+		if v.l == 1 && v.b[0] < 128 {
+			return nil, nil, fmt.Errorf("bad size")
+		}
+		return v, tail, nil
+	}
+	if cur < 0xC0 {
+		intSize := int(cur - 0xB7)
+		if len(b) < intSize+1 {
+			return nil, nil, fmt.Errorf("bad size")
+		}
+		size := readUint(b[1:intSize+1], p.tmp[:])
+		if size < 56 {
+			return nil, nil, fmt.Errorf("bad size")
+		}
+		return p.parseBytes(b[intSize+1:], uint64(intSize), size)
+	}
+	if cur < 0xF8 {
+		return p.parseList(b[1:], 0, int(cur-0xC0))
+	}
+
+	intSize := int(cur - 0xF7)
+	if len(b) < intSize+1 {
+		return nil, nil, fmt.Errorf("bad size")
+	}
+	size := readUint(b[1:intSize+1], p.tmp[:])
+	if size < 56 {
+		return nil, nil, fmt.Errorf("bad size")
+	}
+	return p.parseList(b[intSize+1:], intSize, int(size))
+}
+
+func (p *baselineParser) parseBytes(b []byte, bytes uint64, size uint64) (*baselineValue, []byte, error) {
+	if size > uint64(len(b)) {
+		return nil, nil, fmt.Errorf("length is not enough")
+	}
+
+	v := p.getValue()
+	v.t = TypeBytes
+	v.b = b[:size]
+	v.l = uint64(size)
+	v.i = p.indx
+
+	p.indx += bytes + size + 1
+	return v, b[size:], nil
+}
+
+func (p *baselineParser) parseList(b []byte, bytes int, size int) (*baselineValue, []byte, error) {
+	a := p.getValue()
+	a.t = TypeArray
+	a.a = a.a[:0]
+	a.l = uint64(size)
+	a.i = p.indx
+
+	var v *baselineValue
+	var err error
+
+	p.indx += uint64(bytes) + 1
+	for size > 0 {
+		pre := len(b)
+		v, b, err = p.parseValue(b)
+		if err != nil {
+			return nil, b, fmt.Errorf("cannot parse array value: %s", err)
+		}
+		a.a = append(a.a, v)
+		size -= pre - len(b)
+	}
+	if size < 0 {
+		return nil, nil, fmt.Errorf("bad ending")
+	}
+	return a, b, nil
+}
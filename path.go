@@ -0,0 +1,94 @@
+package fastrlp
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// GetPath returns the value at the given path of array indexes.
+// The error names the index where the navigation failed.
+func (v *Value) GetPath(path ...int) (*Value, error) {
+	for i, indx := range path {
+		if v.t != TypeArray {
+			return nil, fmt.Errorf("%w at path %v", ErrExpectedList, path[:i])
+		}
+		if err := v.decode(); err != nil {
+			return nil, err
+		}
+		if indx < 0 || indx >= len(v.a) {
+			return nil, fmt.Errorf("%w: index %d at path %v in array of %d elements", ErrIndexOutOfRange, indx, path[:i], len(v.a))
+		}
+		v = v.a[indx]
+	}
+	return v, nil
+}
+
+// Exists returns true if there is a value at the given path.
+func (v *Value) Exists(path ...int) bool {
+	_, err := v.GetPath(path...)
+	return err == nil
+}
+
+// GetUint64At returns the uint64 at the given path.
+func (v *Value) GetUint64At(path ...int) (uint64, error) {
+	vv, err := v.GetPath(path...)
+	if err != nil {
+		return 0, err
+	}
+	num, err := vv.GetUint64()
+	if err != nil {
+		return 0, fmt.Errorf("path %v: %w", path, err)
+	}
+	return num, nil
+}
+
+// GetBigIntAt sets b to the big.int at the given path.
+func (v *Value) GetBigIntAt(b *big.Int, path ...int) error {
+	vv, err := v.GetPath(path...)
+	if err != nil {
+		return err
+	}
+	if err := vv.GetBigInt(b); err != nil {
+		return fmt.Errorf("path %v: %w", path, err)
+	}
+	return nil
+}
+
+// GetBytesAt returns the bytes at the given path.
+func (v *Value) GetBytesAt(path ...int) ([]byte, error) {
+	vv, err := v.GetPath(path...)
+	if err != nil {
+		return nil, err
+	}
+	b, err := vv.Bytes()
+	if err != nil {
+		return nil, fmt.Errorf("path %v: %w", path, err)
+	}
+	return b, nil
+}
+
+// GetStringAt returns the string at the given path.
+func (v *Value) GetStringAt(path ...int) (string, error) {
+	vv, err := v.GetPath(path...)
+	if err != nil {
+		return "", err
+	}
+	s, err := vv.GetString()
+	if err != nil {
+		return "", fmt.Errorf("path %v: %w", path, err)
+	}
+	return s, nil
+}
+
+// GetElemsAt returns the elements of the array at the given path.
+func (v *Value) GetElemsAt(path ...int) ([]*Value, error) {
+	vv, err := v.GetPath(path...)
+	if err != nil {
+		return nil, err
+	}
+	elems, err := vv.GetElems()
+	if err != nil {
+		return nil, fmt.Errorf("path %v: %w", path, err)
+	}
+	return elems, nil
+}
@@ -0,0 +1,68 @@
+package fastrlp
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestValueGetPath(t *testing.T) {
+	a := &Arena{}
+
+	// [1, ["dog", [1000]], []]
+	inner := a.NewArray()
+	inner.Set(a.NewUint(1000))
+	list := a.NewArray()
+	list.Set(a.NewString("dog"))
+	list.Set(inner)
+	v := a.NewArray()
+	v.Set(a.NewUint(1))
+	v.Set(list)
+	v.Set(a.NewArray())
+
+	p := &Parser{}
+	pv, err := p.Parse(v.MarshalTo(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []*Value{v, pv} {
+		if num, err := v.GetUint64At(1, 1, 0); err != nil || num != 1000 {
+			t.Fatalf("bad num %d %v", num, err)
+		}
+		b := new(big.Int)
+		if err := v.GetBigIntAt(b, 0); err != nil || b.Uint64() != 1 {
+			t.Fatalf("bad big int %s %v", b, err)
+		}
+		if s, err := v.GetStringAt(1, 0); err != nil || s != "dog" {
+			t.Fatalf("bad string %s %v", s, err)
+		}
+		if b, err := v.GetBytesAt(1, 0); err != nil || string(b) != "dog" {
+			t.Fatalf("bad bytes %s %v", b, err)
+		}
+		if elems, err := v.GetElemsAt(2); err != nil || len(elems) != 0 {
+			t.Fatalf("bad elems %v", err)
+		}
+		if vv, err := v.GetPath(); err != nil || vv != v {
+			t.Fatal("empty path should return the value")
+		}
+
+		if !v.Exists(1, 1, 0) || v.Exists(1, 1, 1) || v.Exists(3) || v.Exists(-1) {
+			t.Fatal("bad exists")
+		}
+		if _, err := v.GetPath(1, 2); !errors.Is(err, ErrIndexOutOfRange) {
+			t.Fatalf("expected out of range error but found %v", err)
+		}
+		if _, err := v.GetPath(1, 0, 0); !errors.Is(err, ErrExpectedList) {
+			t.Fatalf("expected list error but found %v", err)
+		}
+		if _, err := v.GetUint64At(1); !errors.Is(err, ErrExpectedBytes) {
+			t.Fatalf("expected bytes error but found %v", err)
+		}
+
+		// the index equal to the number of elements does not panic
+		if v.Get(3) != nil || v.Get(-1) != nil || v.Get(0).Get(0) != nil {
+			t.Fatal("expected no value")
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package fastrlp
+
+import (
+	"errors"
+)
+
+// PeekSize returns the total size of the value at the start of b. If b does
+// not contain the complete header, it returns the number of bytes required to
+// read the header instead. needMore is set if b is shorter than the returned size.
+func PeekSize(b []byte) (total uint64, needMore bool, err error) {
+	if len(b) == 0 {
+		return 1, true, nil
+	}
+	hdr := headerLen(b[0])
+	if uint64(len(b)) < hdr {
+		return hdr, true, nil
+	}
+	_, hdr, size, err := readSize(b)
+	if err != nil {
+		return 0, false, &ParseError{Kind: err}
+	}
+	if err := checkValueSize(hdr, size); err != nil {
+		return 0, false, &ParseError{Kind: err}
+	}
+	total = hdr + size
+	return total, uint64(len(b)) < total, nil
+}
+
+// PushParser parses RLP values from chunks of input as they arrive, i.e.
+// from a network connection. A value is returned as soon as all its
+// bytes are available.
+type PushParser struct {
+	p Parser
+
+	// off is the position in the buffer of the next value
+	off uint64
+
+	// offset is the position in the stream of the start of the buffer
+	offset uint64
+
+	// err is the last error
+	err error
+}
+
+// SetOptions sets the limits enforced while parsing each value.
+// MaxInputSize limits the size of each value.
+func (pp *PushParser) SetOptions(opts ParserOptions) {
+	pp.p.SetOptions(opts)
+}
+
+// Feed adds a chunk of input and returns the values that are complete.
+// On error, it returns the values parsed before the invalid one.
+//
+// The values are valid until the next call to Feed. b may be modified
+// after the Feed call.
+func (pp *PushParser) Feed(b []byte) ([]*Value, error) {
+	if pp.err != nil {
+		return nil, pp.err
+	}
+
+	// discard the values returned in the previous call
+	pp.offset += pp.off
+	pp.p.buf = append(pp.p.buf[:0], pp.p.buf[pp.off:]...)
+	pp.p.buf = append(pp.p.buf, b...)
+	pp.p.c.reset()
+	pp.off = 0
+
+	var vals []*Value
+	for {
+		buf := pp.p.buf[pp.off:]
+		total, needMore, err := PeekSize(buf)
+		if err != nil {
+			err.(*ParseError).Offset = pp.off
+			return vals, pp.setErr(err)
+		}
+		if limit := pp.p.opts.MaxInputSize; limit != 0 && total > limit {
+			err := newLimitError(ErrMaxInputSize, limit)
+			err.Offset = pp.off
+			return vals, pp.setErr(err)
+		}
+		if needMore {
+			break
+		}
+
+		pp.p.c.indx = pp.off
+		v, _, err := pp.p.parseValue(buf[:total])
+		if err != nil {
+			return vals, pp.setErr(err)
+		}
+		vals = append(vals, v)
+		pp.off += total
+	}
+	return vals, nil
+}
+
+// setErr sets the error with its position in the stream
+func (pp *PushParser) setErr(err error) error {
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		perr.Offset += pp.offset
+	}
+	pp.err = err
+	return err
+}
+
+// Buffered returns the number of bytes of an incomplete value.
+func (pp *PushParser) Buffered() int {
+	return len(pp.p.buf) - int(pp.off)
+}
+
+// Raw returns the raw bytes of a value returned by the last Feed call.
+func (pp *PushParser) Raw(v *Value) []byte {
+	return pp.p.Raw(v)
+}
+
+// Reset discards the buffered input and the last error.
+func (pp *PushParser) Reset() {
+	pp.p.buf = pp.p.buf[:0]
+	pp.p.c.reset()
+	pp.off = 0
+	pp.offset = 0
+	pp.err = nil
+}
@@ -0,0 +1,121 @@
+package fastrlp
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestPeekSize(t *testing.T) {
+	cases := []struct {
+		input    []byte
+		total    uint64
+		needMore bool
+	}{
+		{[]byte{}, 1, true},
+		{[]byte{0x01}, 1, false},
+		{[]byte{0x83, 0x64}, 4, true},
+		{[]byte{0x83, 0x64, 0x6f, 0x67}, 4, false},
+		{[]byte{0xB9, 0x01}, 3, true},
+		{[]byte{0xB9, 0x01, 0x00}, 259, true},
+		{[]byte{0xC0}, 1, false},
+	}
+	for _, c := range cases {
+		total, needMore, err := PeekSize(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != c.total || needMore != c.needMore {
+			t.Fatalf("%x: expected (%d, %v) but found (%d, %v)", c.input, c.total, c.needMore, total, needMore)
+		}
+	}
+
+	if _, _, err := PeekSize([]byte{0xB8, 0x01}); !errors.Is(err, ErrNonCanonicalSize) {
+		t.Fatalf("expected non canonical error but found %v", err)
+	}
+
+	// size that overflows the total length of the value
+	overflow := []byte{0xBF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+	if _, _, err := PeekSize(overflow); !errors.Is(err, ErrSizeOverflow) {
+		t.Fatalf("expected overflow error but found %v", err)
+	}
+
+	var pp PushParser
+	pp.SetOptions(ParserOptions{MaxInputSize: 100})
+	if _, err := pp.Feed(overflow); !errors.Is(err, ErrSizeOverflow) {
+		t.Fatalf("expected overflow error but found %v", err)
+	}
+}
+
+func TestPushParser(t *testing.T) {
+	var vals [][]byte
+	var buf []byte
+	for i := 0; i < 100; i++ {
+		val := generateRandom().MarshalTo(nil)
+		vals = append(vals, val)
+		buf = append(buf, val...)
+	}
+
+	var pp PushParser
+	num := 0
+	for len(buf) > 0 {
+		// feed chunks of random size
+		size := rand.Intn(200)
+		if size > len(buf) {
+			size = len(buf)
+		}
+		res, err := pp.Feed(buf[:size])
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf = buf[size:]
+
+		for _, v := range res {
+			if !bytes.Equal(v.MarshalTo(nil), vals[num]) {
+				t.Fatal("bad value")
+			}
+			if !bytes.Equal(pp.Raw(v), vals[num]) {
+				t.Fatal("bad raw")
+			}
+			num++
+		}
+	}
+	if num != len(vals) {
+		t.Fatalf("expected %d values but found %d", len(vals), num)
+	}
+	if pp.Buffered() != 0 {
+		t.Fatal("expected no buffered bytes")
+	}
+}
+
+func TestPushParserErrors(t *testing.T) {
+	var pp PushParser
+	res, err := pp.Feed([]byte{0x01, 0x83, 0x64})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 1 || pp.Buffered() != 2 {
+		t.Fatal("bad")
+	}
+	res, err = pp.Feed([]byte{0x6f, 0x67, 0xC2, 0x81, 0x01})
+	if !errors.Is(err, ErrNonCanonicalSize) {
+		t.Fatalf("expected non canonical error but found %v", err)
+	}
+	if len(res) != 1 {
+		t.Fatal("expected the values before the error")
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Offset != 6 {
+		t.Fatalf("expected error at offset 6 but found %v", err)
+	}
+	if _, err := pp.Feed([]byte{0x01}); err == nil {
+		t.Fatal("it should keep failing")
+	}
+
+	pp.Reset()
+	pp.SetOptions(ParserOptions{MaxInputSize: 100})
+	if _, err := pp.Feed([]byte{0xB9, 0x01, 0x00}); !errors.Is(err, ErrMaxInputSize) {
+		t.Fatalf("expected limit error but found %v", err)
+	}
+}
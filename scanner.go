@@ -0,0 +1,100 @@
+package fastrlp
+
+// Scanner scans a series of concatenated RLP values.
+//
+// Scanner may parse RLP values from chain export files or
+// batches of devp2p messages.
+type Scanner struct {
+	// p is used to parse the values and holds a copy of the input
+	p Parser
+
+	// off is the offset of the next value in the input
+	off uint64
+
+	// start is the offset of the current value in the input
+	start uint64
+
+	// v is the current value
+	v *Value
+
+	// err is the last error
+	err error
+}
+
+// SetOptions sets the limits enforced while parsing each value.
+// MaxInputSize limits the size of the buffer passed to Init.
+func (sc *Scanner) SetOptions(opts ParserOptions) {
+	sc.p.SetOptions(opts)
+}
+
+// Init initializes sc with the given buffer.
+//
+// b may be modified after the Init call.
+func (sc *Scanner) Init(b []byte) {
+	sc.p.c.reset()
+	sc.p.buf = sc.p.buf[:0]
+	sc.off = 0
+	sc.start = 0
+	sc.v = nil
+	sc.err = nil
+
+	if err := sc.p.checkInputSize(len(b)); err != nil {
+		sc.err = err
+		return
+	}
+	sc.p.buf = append(sc.p.buf, b...)
+}
+
+// Next parses the next RLP value from the buffer.
+//
+// It returns false either on error or on the end of the input.
+// Call Error in order to determine the cause of the returned false.
+func (sc *Scanner) Next() bool {
+	if sc.err != nil {
+		return false
+	}
+	if sc.off == uint64(len(sc.p.buf)) {
+		sc.v = nil
+		return false
+	}
+
+	// values from the previous call are overwritten
+	sc.p.c.reset()
+	sc.p.c.indx = sc.off
+
+	v, tail, err := sc.p.parseValue(sc.p.buf[sc.off:])
+	if err != nil {
+		sc.err = err
+		sc.v = nil
+		return false
+	}
+	sc.start = sc.off
+	sc.off = uint64(len(sc.p.buf) - len(tail))
+	sc.v = v
+	return true
+}
+
+// Error returns the last error.
+func (sc *Scanner) Error() error {
+	return sc.err
+}
+
+// Value returns the last parsed value.
+//
+// The value is valid until the Next call.
+func (sc *Scanner) Value() *Value {
+	return sc.v
+}
+
+// Offset returns the offset of the last parsed value in the input.
+func (sc *Scanner) Offset() uint64 {
+	return sc.start
+}
+
+// Raw returns the raw bytes of the last parsed value.
+func (sc *Scanner) Raw() []byte {
+	if sc.v == nil {
+		return nil
+	}
+	return sc.p.Raw(sc.v)
+}
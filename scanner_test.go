@@ -0,0 +1,72 @@
+package fastrlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestScanner(t *testing.T) {
+	var vals []*Value
+	for i := 0; i < 100; i++ {
+		vals = append(vals, generateRandom())
+	}
+
+	var buf []byte
+	var offsets []uint64
+	for _, v := range vals {
+		offsets = append(offsets, uint64(len(buf)))
+		buf = v.MarshalTo(buf)
+	}
+
+	var sc Scanner
+	sc.Init(buf)
+
+	num := 0
+	for sc.Next() {
+		if sc.Offset() != offsets[num] {
+			t.Fatalf("bad offset, expected %d but found %d", offsets[num], sc.Offset())
+		}
+		expected := vals[num].MarshalTo(nil)
+		if !bytes.Equal(sc.Value().MarshalTo(nil), expected) {
+			t.Fatal("bad value")
+		}
+		if !bytes.Equal(sc.Raw(), expected) {
+			t.Fatal("bad raw")
+		}
+		if !checkRaw(&sc.p, sc.Value()) {
+			t.Fatal("bad")
+		}
+		num++
+	}
+	if err := sc.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if num != len(vals) {
+		t.Fatalf("expected %d values but found %d", len(vals), num)
+	}
+}
+
+func TestScannerInvalid(t *testing.T) {
+	buf, err := hex.DecodeString("0183646f67b8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sc Scanner
+	sc.Init(buf)
+
+	num := 0
+	for sc.Next() {
+		num++
+	}
+	if num != 2 {
+		t.Fatalf("expected 2 values but found %d", num)
+	}
+	if sc.Error() == nil {
+		t.Fatal("it should fail")
+	}
+	if sc.Next() {
+		t.Fatal("it should not continue after an error")
+	}
+}
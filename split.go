@@ -0,0 +1,88 @@
+package fastrlp
+
+import (
+	"fmt"
+)
+
+// Split returns the type and content of the first value in b and the
+// bytes after it. It performs the same size checks as Parser.Parse.
+func Split(b []byte) (Type, []byte, []byte, error) {
+	t, hdr, size, err := readHeader(b)
+	if err != nil {
+		return 0, nil, b, &ParseError{Kind: err}
+	}
+	return t, b[hdr : hdr+size], b[hdr+size:], nil
+}
+
+// SplitList splits b into the content of a list and the bytes after it.
+func SplitList(b []byte) ([]byte, []byte, error) {
+	t, content, rest, err := Split(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if t != TypeArray {
+		return nil, b, ErrExpectedList
+	}
+	return content, rest, nil
+}
+
+// SplitString splits b into the content of a bytes value and the bytes after it.
+func SplitString(b []byte) ([]byte, []byte, error) {
+	t, content, rest, err := Split(b)
+	if err != nil {
+		return nil, b, err
+	}
+	if t != TypeBytes {
+		return nil, b, ErrExpectedBytes
+	}
+	return content, rest, nil
+}
+
+// SplitUint64 decodes an integer at the start of b and returns the bytes after it.
+// Non-canonical integers are rejected.
+func SplitUint64(b []byte) (uint64, []byte, error) {
+	content, rest, err := SplitString(b)
+	if err != nil {
+		return 0, b, err
+	}
+	switch {
+	case len(content) == 0:
+		return 0, rest, nil
+	case len(content) == 1 && content[0] == 0:
+		return 0, b, ErrCanonZero
+	case len(content) > 8:
+		return 0, b, fmt.Errorf("%w: %d bytes", ErrUint64Range, len(content))
+	case content[0] == 0:
+		return 0, b, ErrCanonInt
+	}
+	var buf [8]byte
+	return readUint(content, buf[:]), rest, nil
+}
+
+// Skip returns the bytes after the first value in b.
+func Skip(b []byte) ([]byte, error) {
+	_, _, rest, err := Split(b)
+	return rest, err
+}
+
+// CountValues returns the number of values in the content of a list.
+func CountValues(b []byte) (int, error) {
+	var pos uint64
+	num := 0
+	for ; pos < uint64(len(b)); num++ {
+		_, hdr, size, err := readHeader(b[pos:])
+		if err != nil {
+			return 0, &ParseError{Offset: pos, Path: []int{num}, Kind: err}
+		}
+		pos += hdr + size
+	}
+	return num, nil
+}
+
+// ListSize returns the encoded size of a list with the given content length.
+func ListSize(contentLen uint64) uint64 {
+	if contentLen < 56 {
+		return 1 + contentLen
+	}
+	return 1 + intsize(contentLen) + contentLen
+}
@@ -0,0 +1,140 @@
+package fastrlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		input   string
+		typ     Type
+		content string
+		rest    string
+		err     error
+	}{
+		{"01", TypeBytes, "01", "", nil},
+		{"80", TypeBytes, "", "", nil},
+		{"83646f67ff", TypeBytes, "646f67", "ff", nil},
+		{"c20102", TypeArray, "0102", "", nil},
+		{"c0c0", TypeArray, "", "c0", nil},
+		{"", 0, "", "", ErrTruncated},
+		{"83646f", 0, "", "", ErrTruncated},
+		{"8101", 0, "", "", ErrNonCanonicalSize},
+		{"b80101", 0, "", "", ErrNonCanonicalSize},
+	}
+
+	for _, c := range cases {
+		buf, _ := hex.DecodeString(c.input)
+		typ, content, rest, err := Split(buf)
+		if c.err != nil {
+			if !errors.Is(err, c.err) {
+				t.Fatalf("%s: expected %v but found %v", c.input, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if typ != c.typ {
+			t.Fatalf("%s: expected %s but found %s", c.input, c.typ, typ)
+		}
+		if hex.EncodeToString(content) != c.content {
+			t.Fatalf("%s: bad content %x", c.input, content)
+		}
+		if hex.EncodeToString(rest) != c.rest {
+			t.Fatalf("%s: bad rest %x", c.input, rest)
+		}
+	}
+}
+
+func TestSplitTypes(t *testing.T) {
+	buf, _ := hex.DecodeString("c483646f6701")
+
+	content, rest, err := SplitList(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 1 {
+		t.Fatal("bad rest")
+	}
+	if _, _, err := SplitString(buf); !errors.Is(err, ErrExpectedBytes) {
+		t.Fatalf("expected bytes error but found %v", err)
+	}
+
+	num, err := CountValues(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num != 1 {
+		t.Fatalf("expected 1 value but found %d", num)
+	}
+
+	str, rest, err := SplitString(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(str) != "dog" || len(rest) != 0 {
+		t.Fatal("bad string")
+	}
+	if _, _, err := SplitList(content); !errors.Is(err, ErrExpectedList) {
+		t.Fatalf("expected list error but found %v", err)
+	}
+
+	rest, err = Skip(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, []byte{0x01}) {
+		t.Fatal("bad skip")
+	}
+}
+
+func TestSplitUint64(t *testing.T) {
+	cases := []struct {
+		input string
+		num   uint64
+		err   error
+	}{
+		{"80", 0, nil},
+		{"01", 1, nil},
+		{"8203e8", 1000, nil},
+		{"88ffffffffffffffff", 0xffffffffffffffff, nil},
+		{"00", 0, ErrCanonZero},
+		{"820001", 0, ErrCanonInt},
+		{"89010000000000000000", 0, ErrUint64Range},
+		{"c0", 0, ErrExpectedBytes},
+	}
+
+	for _, c := range cases {
+		buf, _ := hex.DecodeString(c.input)
+		num, _, err := SplitUint64(buf)
+		if !errors.Is(err, c.err) {
+			t.Fatalf("%s: expected %v but found %v", c.input, c.err, err)
+		}
+		if num != c.num {
+			t.Fatalf("%s: expected %d but found %d", c.input, c.num, num)
+		}
+	}
+}
+
+func TestCountValuesInvalid(t *testing.T) {
+	buf, _ := hex.DecodeString("0183646f")
+	if _, err := CountValues(buf); !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected truncated error but found %v", err)
+	}
+}
+
+func TestListSize(t *testing.T) {
+	a := &Arena{}
+	for _, size := range []int{0, 1, 55, 56, 1000, 100000} {
+		v := a.NewArray()
+		v.Set(a.NewBytes(make([]byte, size)))
+		content := a.NewBytes(make([]byte, size)).MarshalTo(nil)
+		if ListSize(uint64(len(content))) != uint64(len(v.MarshalTo(nil))) {
+			t.Fatalf("bad list size for %d", size)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package fastrlp
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Tape is a parsed RLP value stored as a flat list of offsets, lengths
+// and types over the input buffer. Unlike the Value tree, it does not
+// contain any pointer besides the buffers, which makes it cheap for the GC.
+//
+// Each value takes the following words in the tape:
+//
+//	bytes: header, content length
+//	array: header, content length, number of elements, index of the next sibling
+//
+// where the header holds the type in the upper 4 bits, the header length in the
+// next 4 bits and the offset of the value in the input in the lower 56 bits.
+type Tape struct {
+	buf    []byte
+	t      []uint64
+	strict bool
+}
+
+const (
+	tapeTypeShift   = 60
+	tapeHeaderShift = 56
+	tapeOffsetMask  = 1<<tapeHeaderShift - 1
+)
+
+// ParseTape parses a complete rlp encoding into a Tape.
+//
+// The tape is valid until the next call to any of the parse methods.
+func (p *Parser) ParseTape(b []byte) (*Tape, error) {
+	if err := p.checkInputSize(len(b)); err != nil {
+		return nil, err
+	}
+
+	p.buf = append(p.buf[:0], b...)
+	p.tape.buf = p.buf
+	p.tape.t = p.tape.t[:0]
+	p.tape.strict = p.opts.Strict
+
+	size, err := p.parseTape(p.buf)
+	if err != nil {
+		return nil, err
+	}
+	if size != uint64(len(p.buf)) {
+		return nil, &ParseError{Offset: size, Kind: ErrTrailingData}
+	}
+	return &p.tape, nil
+}
+
+// parseTape appends the value at the start of b to the tape and returns its size.
+func (p *Parser) parseTape(b []byte) (uint64, error) {
+	t := &p.tape
+	p.w.reset(b, 0, 0, p.opts)
+	p.tstack = p.tstack[:0]
+
+	for {
+		ev, pos, hdr, size, err := p.w.next()
+		if err != nil {
+			return 0, err
+		}
+
+		if ev == walkLeave {
+			n := p.tstack[len(p.tstack)-1]
+			p.tstack = p.tstack[:len(p.tstack)-1]
+			t.t[n+3] = uint64(len(t.t))
+		} else {
+			if len(p.tstack) != 0 {
+				t.t[p.tstack[len(p.tstack)-1]+2]++
+			}
+			n := len(t.t)
+			if ev == walkBytes {
+				t.t = append(t.t, uint64(TypeBytes)<<tapeTypeShift|hdr<<tapeHeaderShift|pos, size)
+			} else {
+				// the number of elements and the next sibling are set while
+				// the elements of the list are parsed
+				t.t = append(t.t, uint64(TypeArray)<<tapeTypeShift|hdr<<tapeHeaderShift|pos, size, 0, 0)
+				p.tstack = append(p.tstack, n)
+				continue
+			}
+		}
+
+		if len(p.tstack) == 0 {
+			return p.w.pos, nil
+		}
+	}
+}
+
+// Root returns a cursor to the root value of the tape.
+func (t *Tape) Root() Cursor {
+	return Cursor{t: t}
+}
+
+// Cursor is a reference to a value in a Tape.
+type Cursor struct {
+	t *Tape
+	n int
+}
+
+func (c Cursor) header() uint64 {
+	return c.t.t[c.n]
+}
+
+// Type returns the type of the value.
+func (c Cursor) Type() Type {
+	return Type(c.header() >> tapeTypeShift)
+}
+
+// Len returns the length of the content of the value.
+func (c Cursor) Len() uint64 {
+	return c.t.t[c.n+1]
+}
+
+// Elems returns the number of elements if its an array.
+func (c Cursor) Elems() int {
+	if c.Type() != TypeArray {
+		return 0
+	}
+	return int(c.t.t[c.n+2])
+}
+
+// next returns the index in the tape of the next sibling
+func (c Cursor) next() int {
+	if c.Type() == TypeArray {
+		return int(c.t.t[c.n+3])
+	}
+	return c.n + 2
+}
+
+// Get returns the element at index i in the array.
+func (c Cursor) Get(i int) (Cursor, error) {
+	if c.Type() != TypeArray {
+		return Cursor{}, ErrExpectedList
+	}
+	if i < 0 || i >= c.Elems() {
+		return Cursor{}, fmt.Errorf("%w: index %d in array of %d elements", ErrIndexOutOfRange, i, c.Elems())
+	}
+	elem := Cursor{t: c.t, n: c.n + 4}
+	for ; i > 0; i-- {
+		elem.n = elem.next()
+	}
+	return elem, nil
+}
+
+// ForEach calls fn for each element of the array. It stops if fn returns an error.
+func (c Cursor) ForEach(fn func(i int, elem Cursor) error) error {
+	if c.Type() != TypeArray {
+		return ErrExpectedList
+	}
+	elem := Cursor{t: c.t, n: c.n + 4}
+	for i := 0; i < c.Elems(); i++ {
+		if err := fn(i, elem); err != nil {
+			return err
+		}
+		elem.n = elem.next()
+	}
+	return nil
+}
+
+// Raw returns the raw bytes of the value.
+func (c Cursor) Raw() []byte {
+	h := c.header()
+	start := h & tapeOffsetMask
+	return c.t.buf[start : start+(h>>tapeHeaderShift&0xF)+c.Len()]
+}
+
+// Bytes returns the content of a bytes value.
+func (c Cursor) Bytes() ([]byte, error) {
+	if c.Type() != TypeBytes {
+		return nil, ErrExpectedBytes
+	}
+	h := c.header()
+	start := h&tapeOffsetMask + h>>tapeHeaderShift&0xF
+	return c.t.buf[start : start+c.Len()], nil
+}
+
+// GetBytes returns bytes to dst.
+func (c Cursor) GetBytes(dst []byte, bits ...int) ([]byte, error) {
+	b, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	if len(bits) > 0 {
+		if len(b) != bits[0] {
+			return nil, fmt.Errorf("%w, expected %d but found %d", ErrBadLength, bits[0], len(b))
+		}
+	}
+	dst = append(dst[:0], b...)
+	return dst, nil
+}
+
+// GetUint64 returns uint64.
+func (c Cursor) GetUint64() (uint64, error) {
+	v, err := c.value()
+	if err != nil {
+		return 0, err
+	}
+	return v.GetUint64()
+}
+
+// GetBigInt returns big.int value.
+func (c Cursor) GetBigInt(b *big.Int) error {
+	v, err := c.value()
+	if err != nil {
+		return err
+	}
+	return v.GetBigInt(b)
+}
+
+// GetBool returns bool value.
+func (c Cursor) GetBool() (bool, error) {
+	v, err := c.value()
+	if err != nil {
+		return false, err
+	}
+	return v.GetBool()
+}
+
+// value returns a bytes Value with the content of the cursor to reuse its getters
+func (c Cursor) value() (Value, error) {
+	if c.Type() != TypeBytes {
+		return Value{}, ErrExpectedBytes
+	}
+	hdr := c.header() >> tapeHeaderShift & 0xF
+	return Value{t: TypeBytes, enc: c.Raw(), hdr: uint8(hdr), l: c.Len(), strict: c.t.strict}, nil
+}
@@ -0,0 +1,161 @@
+package fastrlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestTape(t *testing.T) {
+	p0, p1 := &Parser{}, &Parser{}
+	for i := 0; i < 1000; i++ {
+		buf := generateRandom().MarshalTo(nil)
+
+		v, err := p0.Parse(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tape, err := p1.ParseTape(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := checkTape(p0, v, tape.Root()); err != nil {
+			t.Fatal(err)
+		}
+
+		// corrupt the input and check that both return the same error
+		buf[rand.Intn(len(buf))] ^= byte(1 + rand.Intn(255))
+		_, err0 := p0.Parse(buf)
+		_, err1 := p1.ParseTape(buf)
+		if !reflect.DeepEqual(err0, err1) {
+			t.Fatalf("expected '%v' but found '%v'", err0, err1)
+		}
+	}
+}
+
+func checkTape(p *Parser, v *Value, c Cursor) error {
+	if v.Type() != c.Type() {
+		return fmt.Errorf("bad tape type")
+	}
+	if !bytes.Equal(p.Raw(v), c.Raw()) {
+		return fmt.Errorf("bad tape raw")
+	}
+	if v.Type() == TypeBytes {
+		b, err := c.Bytes()
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(v.content(), b) {
+			return fmt.Errorf("bad tape bytes")
+		}
+		return nil
+	}
+	if v.Elems() != c.Elems() {
+		return fmt.Errorf("bad tape elems")
+	}
+	for i := 0; i < v.Elems(); i++ {
+		elem, err := c.Get(i)
+		if err != nil {
+			return err
+		}
+		if err := checkTape(p, v.Get(i), elem); err != nil {
+			return err
+		}
+	}
+	num := 0
+	err := c.ForEach(func(i int, elem Cursor) error {
+		num++
+		return checkTape(p, v.Get(i), elem)
+	})
+	if err != nil {
+		return err
+	}
+	if num != v.Elems() {
+		return fmt.Errorf("bad tape foreach")
+	}
+	return nil
+}
+
+func TestTapeGetters(t *testing.T) {
+	a := &Arena{}
+	v := a.NewArray()
+	v.Set(a.NewUint(1000))
+	v.Set(a.NewString("dog"))
+	v.Set(a.NewArray())
+
+	p := &Parser{}
+	tape, err := p.ParseTape(v.MarshalTo(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tape.Root()
+
+	elem, _ := root.Get(0)
+	if num, err := elem.GetUint64(); err != nil || num != 1000 {
+		t.Fatalf("bad uint %d %v", num, err)
+	}
+	elem, _ = root.Get(1)
+	if b, err := elem.GetBytes(nil, 3); err != nil || string(b) != "dog" {
+		t.Fatalf("bad bytes %s %v", b, err)
+	}
+	elem, _ = root.Get(2)
+	if elem.Type() != TypeArray || elem.Elems() != 0 {
+		t.Fatal("bad array")
+	}
+	if _, err := root.Get(3); !errors.Is(err, ErrIndexOutOfRange) {
+		t.Fatalf("expected index out of range but found %v", err)
+	}
+	if _, err := elem.Bytes(); err == nil {
+		t.Fatal("it should fail")
+	}
+}
+
+func TestTapeErrors(t *testing.T) {
+	cases := []struct {
+		input string
+		opts  ParserOptions
+	}{
+		{"", ParserOptions{}},
+		{"83646f", ParserOptions{}},
+		{"c3018101", ParserOptions{}},
+		{"c4c3b80101", ParserOptions{}},
+		{"c30182646f", ParserOptions{}},
+		{"01ffff", ParserOptions{}},
+		{"c2c1c0", ParserOptions{MaxDepth: 2}},
+		{"c3010203", ParserOptions{MaxListElems: 2}},
+		{"c3c28301", ParserOptions{MaxBytesLen: 2}},
+	}
+
+	for _, c := range cases {
+		buf, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, parseErr := NewParser(c.opts).Parse(buf)
+		if parseErr == nil {
+			t.Fatalf("%s: parse should fail", c.input)
+		}
+		// the tape must fail with the same error as Parse
+		if _, err := NewParser(c.opts).ParseTape(buf); !reflect.DeepEqual(err, parseErr) {
+			t.Fatalf("%s: expected '%v' but found '%v'", c.input, parseErr, err)
+		}
+	}
+}
+
+func BenchmarkParseTape(b *testing.B) {
+	buf := benchmarkParserInput()
+	p := &Parser{}
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		if _, err := p.ParseTape(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package fastrlp
+
+import (
+	"bufio"
+	"io"
+)
+
+// TokenKind is the kind of a Token.
+type TokenKind int
+
+const (
+	// TokenListStart is the start of an array.
+	TokenListStart TokenKind = iota
+
+	// TokenBytes is a bytes value.
+	TokenBytes
+
+	// TokenListEnd is the end of an array.
+	TokenListEnd
+)
+
+// String returns the string representation of the token kind.
+func (k TokenKind) String() string {
+	switch k {
+	case TokenListStart:
+		return "list-start"
+	case TokenBytes:
+		return "bytes"
+	case TokenListEnd:
+		return "list-end"
+	default:
+		return "unknown"
+	}
+}
+
+// Token is an element of an RLP stream.
+type Token struct {
+	// Kind is the kind of the token
+	Kind TokenKind
+
+	// Offset is the position of the value in the stream. For
+	// TokenListEnd it is the position after the array
+	Offset uint64
+
+	// Len is the length of the content of the value
+	Len uint64
+
+	// Data is the content of a bytes value. It is valid until the next call to Token
+	Data []byte
+}
+
+// tokenFrame is an open array in the Tokenizer
+type tokenFrame struct {
+	// end is the position in the stream where the array ends
+	end uint64
+
+	// elems is the number of elements read from the array
+	elems int
+}
+
+// Tokenizer reads the tokens of a stream of RLP values without
+// building any Value. It only buffers the content of the last
+// bytes value.
+type Tokenizer struct {
+	r      *bufio.Reader
+	buf    []byte
+	stack  []tokenFrame
+	opts   ParserOptions
+	offset uint64
+	err    error
+}
+
+// NewTokenizer returns a tokenizer that reads from r.
+func NewTokenizer(r io.Reader) *Tokenizer {
+	return &Tokenizer{
+		r: bufio.NewReader(r),
+	}
+}
+
+// SetOptions sets the limits enforced by the tokenizer.
+// MaxInputSize limits the size of each top level value.
+func (t *Tokenizer) SetOptions(opts ParserOptions) {
+	t.opts = opts
+}
+
+// Token returns the next token in the stream. It returns io.EOF
+// at the end of the stream if all the arrays are closed.
+func (t *Tokenizer) Token() (Token, error) {
+	if t.err != nil {
+		return Token{}, t.err
+	}
+	tok, err := t.token()
+	if err != nil {
+		t.err = err
+	}
+	return tok, err
+}
+
+func (t *Tokenizer) token() (Token, error) {
+	if n := len(t.stack); n != 0 && t.stack[n-1].end == t.offset {
+		t.stack = t.stack[:n-1]
+		return Token{Kind: TokenListEnd, Offset: t.offset}, nil
+	}
+
+	// read the header
+	cur, err := t.r.ReadByte()
+	if err != nil {
+		if err == io.EOF && len(t.stack) != 0 {
+			return Token{}, t.newError(ErrTruncated, io.ErrUnexpectedEOF)
+		}
+		return Token{}, err
+	}
+	t.buf = append(t.buf[:0], cur)
+
+	hdr := headerLen(cur)
+	if hdr > 1 {
+		if err := t.read(hdr - 1); err != nil {
+			return Token{}, err
+		}
+	}
+	typ, hdr, size, err := readSize(t.buf)
+	if err != nil {
+		return Token{}, t.newError(err, nil)
+	}
+
+	// the sizes are compared without adding them so that they cannot overflow
+	if len(t.stack) != 0 {
+		top := &t.stack[len(t.stack)-1]
+		if t.opts.MaxListElems != 0 && top.elems >= t.opts.MaxListElems {
+			return Token{}, t.newError(ErrMaxListElems, &LimitError{Err: ErrMaxListElems, Limit: uint64(t.opts.MaxListElems)})
+		}
+		if remaining := top.end - t.offset; hdr > remaining || size > remaining-hdr {
+			return Token{}, t.newError(ErrElemTooLarge, nil)
+		}
+	} else if limit := t.opts.MaxInputSize; limit != 0 && (hdr > limit || size > limit-hdr) {
+		return Token{}, t.newError(ErrMaxInputSize, &LimitError{Err: ErrMaxInputSize, Limit: limit})
+	}
+	if err := checkValueSize(hdr, size); err != nil {
+		return Token{}, t.newError(err, nil)
+	}
+	total := hdr + size
+
+	tok := Token{Offset: t.offset, Len: size}
+	if typ == TypeBytes {
+		if t.opts.MaxBytesLen != 0 && size > t.opts.MaxBytesLen {
+			return Token{}, t.newError(ErrMaxBytesLen, &LimitError{Err: ErrMaxBytesLen, Limit: t.opts.MaxBytesLen})
+		}
+		// single byte values do not have a header and
+		// are already in the buffer
+		if hdr != 0 {
+			t.buf = t.buf[:0]
+			if err := t.read(size); err != nil {
+				return Token{}, err
+			}
+			if size == 1 && t.buf[0] < 0x80 {
+				return Token{}, t.newError(ErrNonCanonicalSize, nil)
+			}
+		}
+		tok.Kind = TokenBytes
+		tok.Data = t.buf
+	} else {
+		if t.opts.MaxDepth != 0 && len(t.stack) >= t.opts.MaxDepth {
+			return Token{}, t.newError(ErrMaxDepth, &LimitError{Err: ErrMaxDepth, Limit: uint64(t.opts.MaxDepth)})
+		}
+		tok.Kind = TokenListStart
+	}
+
+	if len(t.stack) != 0 {
+		t.stack[len(t.stack)-1].elems++
+	}
+	if typ == TypeArray {
+		t.stack = append(t.stack, tokenFrame{end: t.offset + total})
+		t.offset += hdr
+	} else {
+		t.offset += total
+	}
+	return tok, nil
+}
+
+// read appends size bytes from the stream to the buffer
+func (t *Tokenizer) read(size uint64) error {
+	var err error
+	if t.buf, err = appendRead(t.r, t.buf, size); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return t.newError(ErrTruncated, io.ErrUnexpectedEOF)
+		}
+		return err
+	}
+	return nil
+}
+
+// newError returns an error for the value at the current offset
+func (t *Tokenizer) newError(kind, err error) *ParseError {
+	perr := &ParseError{Offset: t.offset, Kind: kind, Err: err}
+	if len(t.stack) != 0 {
+		perr.Path = make([]int, len(t.stack))
+		for i, f := range t.stack {
+			perr.Path[i] = f.elems - 1
+		}
+		perr.Path[len(t.stack)-1]++
+	}
+	return perr
+}
+
+// Depth returns the number of open arrays.
+func (t *Tokenizer) Depth() int {
+	return len(t.stack)
+}
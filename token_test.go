@@ -0,0 +1,130 @@
+package fastrlp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestTokenizer(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		buf := generateRandom().MarshalTo(nil)
+
+		// rebuild the value from the tokens
+		a := &Arena{}
+		var stack []*Value
+		var res *Value
+
+		tk := NewTokenizer(bytes.NewReader(buf))
+		for {
+			tok, err := tk.Token()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var v *Value
+			switch tok.Kind {
+			case TokenListStart:
+				stack = append(stack, a.NewArray())
+				continue
+			case TokenBytes:
+				if tok.Len != uint64(len(tok.Data)) {
+					t.Fatal("bad len")
+				}
+				v = a.NewCopyBytes(tok.Data)
+			case TokenListEnd:
+				v = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				res = v
+			} else {
+				stack[len(stack)-1].Set(v)
+			}
+		}
+		if !bytes.Equal(res.MarshalTo(nil), buf) {
+			t.Fatal("bad")
+		}
+	}
+}
+
+func TestTokenizerOffsets(t *testing.T) {
+	// ["dog", [], 1]
+	buf := []byte{0xC6, 0x83, 0x64, 0x6f, 0x67, 0xC0, 0x01}
+
+	expected := []Token{
+		{Kind: TokenListStart, Offset: 0, Len: 6},
+		{Kind: TokenBytes, Offset: 1, Len: 3, Data: []byte("dog")},
+		{Kind: TokenListStart, Offset: 5, Len: 0},
+		{Kind: TokenListEnd, Offset: 6},
+		{Kind: TokenBytes, Offset: 6, Len: 1, Data: []byte{0x01}},
+		{Kind: TokenListEnd, Offset: 7},
+	}
+
+	tk := NewTokenizer(bytes.NewReader(buf))
+	for _, exp := range expected {
+		tok, err := tk.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tok.Kind != exp.Kind || tok.Offset != exp.Offset || tok.Len != exp.Len || !bytes.Equal(tok.Data, exp.Data) {
+			t.Fatalf("expected %v but found %v", exp, tok)
+		}
+	}
+	if _, err := tk.Token(); err != io.EOF {
+		t.Fatalf("expected EOF but found %v", err)
+	}
+}
+
+func TestTokenizerErrors(t *testing.T) {
+	cases := []struct {
+		input []byte
+		kind  error
+	}{
+		{[]byte{0xC2, 0x01}, ErrTruncated},
+		{[]byte{0x83, 0x64}, ErrTruncated},
+		{[]byte{0xC2, 0x81, 0x01}, ErrNonCanonicalSize},
+		{[]byte{0xB8, 0x01, 0x01}, ErrNonCanonicalSize},
+		{[]byte{0xC3, 0xC2, 0x01, 0x82, 0x01}, ErrElemTooLarge},
+		{[]byte{0xBF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, ErrSizeOverflow},
+		// the total size of the element wraps inside a list with enough content
+		{append([]byte{0xCB, 0xBF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 0x01, 0x02), ErrElemTooLarge},
+		// the header claims 2GB but only a few bytes arrive
+		{[]byte{0xBB, 0x80, 0x00, 0x00, 0x00, 0x01, 0x02}, ErrTruncated},
+	}
+
+	for _, c := range cases {
+		tk := NewTokenizer(bytes.NewReader(c.input))
+		var err error
+		for err == nil {
+			_, err = tk.Token()
+		}
+		if !errors.Is(err, c.kind) {
+			t.Fatalf("%x: expected %v but found %v", c.input, c.kind, err)
+		}
+	}
+}
+
+func TestTokenizerLimits(t *testing.T) {
+	tk := NewTokenizer(bytes.NewReader([]byte{0xC2, 0xC1, 0xC0}))
+	tk.SetOptions(ParserOptions{MaxDepth: 2})
+
+	var err error
+	for err == nil {
+		_, err = tk.Token()
+	}
+	if !errors.Is(err, ErrMaxDepth) {
+		t.Fatalf("expected depth error but found %v", err)
+	}
+
+	// size that overflows the total length of the value
+	tk = NewTokenizer(bytes.NewReader([]byte{0xBF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}))
+	tk.SetOptions(ParserOptions{MaxInputSize: 100})
+	if _, err := tk.Token(); !errors.Is(err, ErrMaxInputSize) {
+		t.Fatalf("expected limit error but found %v", err)
+	}
+}
@@ -0,0 +1,33 @@
+package fastrlp
+
+// Validate validates that b is a single well-formed RLP value.
+//
+// It performs the same checks as Parser.Parse and returns the same
+// errors without allocating any Value.
+func Validate(b []byte) error {
+	return ValidateWithLimits(b, ParserOptions{})
+}
+
+// ValidateWithLimits validates that b is a single well-formed RLP value
+// that does not exceed the limits in opts.
+func ValidateWithLimits(b []byte, opts ParserOptions) error {
+	if opts.MaxInputSize != 0 && uint64(len(b)) > opts.MaxInputSize {
+		return newLimitError(ErrMaxInputSize, opts.MaxInputSize)
+	}
+
+	var w walker
+	w.reset(b, 0, 0, opts)
+
+	for {
+		if _, _, _, _, err := w.next(); err != nil {
+			return err
+		}
+		if w.done() {
+			break
+		}
+	}
+	if w.pos != uint64(len(b)) {
+		return &ParseError{Offset: w.pos, Kind: ErrTrailingData}
+	}
+	return nil
+}
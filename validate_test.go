@@ -0,0 +1,105 @@
+package fastrlp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		buf := generateRandom().MarshalTo(nil)
+		if err := Validate(buf); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestValidateInvalid(t *testing.T) {
+	cases := []struct {
+		input string
+		opts  ParserOptions
+	}{
+		{"", ParserOptions{}},
+		{"83646f", ParserOptions{}},
+		{"c3018101", ParserOptions{}},
+		{"c4c3b80101", ParserOptions{}},
+		{"c30182646f", ParserOptions{}},
+		{"01ffff", ParserOptions{}},
+		{"f80180", ParserOptions{}},
+		{"ffffffffffffffffff01", ParserOptions{}},
+		{"c2c1c0", ParserOptions{MaxDepth: 2}},
+		{"c3010203", ParserOptions{MaxListElems: 2}},
+		{"c3c28301", ParserOptions{MaxBytesLen: 2}},
+		{"83646f67", ParserOptions{MaxInputSize: 3}},
+	}
+
+	for _, c := range cases {
+		buf, err := hex.DecodeString(c.input)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, parseErr := NewParser(c.opts).Parse(buf)
+		if parseErr == nil {
+			t.Fatalf("%s: parse should fail", c.input)
+		}
+		err = ValidateWithLimits(buf, c.opts)
+		if err == nil {
+			t.Fatalf("%s: validate should fail", c.input)
+		}
+		// both errors must be the same
+		if !reflect.DeepEqual(err, parseErr) {
+			t.Fatalf("%s: expected '%v' but found '%v'", c.input, parseErr, err)
+		}
+
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%s: expected a parse error", c.input)
+		}
+	}
+}
+
+func TestValidateDeep(t *testing.T) {
+	if err := Validate(benchmarkDeepInput()); err != nil {
+		t.Fatal(err)
+	}
+
+	// nested lists that never end are validated without recursion
+	buf := bytes.Repeat([]byte{0xC1}, 1<<18)
+
+	_, parseErr := (&Parser{}).Parse(buf)
+	err := Validate(buf)
+	if !errors.Is(err, ErrTruncated) {
+		t.Fatalf("expected truncated but found %v", err)
+	}
+	if !reflect.DeepEqual(err, parseErr) {
+		t.Fatal("validate and parse errors should be the same")
+	}
+}
+
+func TestValidateAllocs(t *testing.T) {
+	buf := generateRandom().MarshalTo(nil)
+	allocs := testing.AllocsPerRun(100, func() {
+		if err := Validate(buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("expected no allocations but found %f", allocs)
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	buf := generateRandom().MarshalTo(nil)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(buf)))
+	for i := 0; i < b.N; i++ {
+		if err := Validate(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
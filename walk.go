@@ -0,0 +1,95 @@
+package fastrlp
+
+// WalkAction tells Walk how to continue after visiting a value.
+type WalkAction int
+
+const (
+	// WalkContinue visits the elements of the value if it is an array.
+	WalkContinue WalkAction = iota
+
+	// WalkSkip does not visit the elements of the value.
+	WalkSkip
+
+	// WalkStop stops the walk.
+	WalkStop
+)
+
+// WalkFunc is called for each value in a walk. path are the indexes of the
+// value from the root and it is only valid until the function returns.
+type WalkFunc func(path []int, v *Value) WalkAction
+
+// Walk calls fn for each value in the tree of v in depth-first order, starting
+// with v itself. Lazy arrays are decoded as they are visited.
+func (v *Value) Walk(fn WalkFunc) error {
+	return v.WalkEnterLeave(fn, nil)
+}
+
+// WalkEnterLeave is like Walk but it also calls leave after all the elements of
+// a value have been visited. leave is called for every value passed to enter
+// unless the walk is stopped. Any of the functions may be nil.
+func (v *Value) WalkEnterLeave(enter, leave WalkFunc) error {
+	var path []int
+	_, err := v.walk(&path, enter, leave)
+	return err
+}
+
+// walk returns false if the walk was stopped
+func (v *Value) walk(path *[]int, enter, leave WalkFunc) (bool, error) {
+	action := WalkContinue
+	if enter != nil {
+		action = enter(*path, v)
+	}
+	if action == WalkStop {
+		return false, nil
+	}
+	if action == WalkContinue && v.t == TypeArray {
+		if err := v.decode(); err != nil {
+			return false, err
+		}
+		for i, elem := range v.a {
+			*path = append(*path, i)
+			ok, err := elem.walk(path, enter, leave)
+			*path = (*path)[:len(*path)-1]
+			if !ok || err != nil {
+				return false, err
+			}
+		}
+	}
+	if leave != nil && leave(*path, v) == WalkStop {
+		return false, nil
+	}
+	return true, nil
+}
+
+// Find returns the first value in the tree of v in depth-first order
+// for which pred returns true or nil if there is none.
+func (v *Value) Find(pred func(path []int, v *Value) bool) (*Value, error) {
+	var res *Value
+	err := v.Walk(func(path []int, v *Value) WalkAction {
+		if pred(path, v) {
+			res = v
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// FindAll returns all the values in the tree of v in depth-first
+// order for which pred returns true.
+func (v *Value) FindAll(pred func(path []int, v *Value) bool) ([]*Value, error) {
+	var res []*Value
+	err := v.Walk(func(path []int, v *Value) WalkAction {
+		if pred(path, v) {
+			res = append(res, v)
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -0,0 +1,155 @@
+package fastrlp
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func newWalkValue(a *Arena) *Value {
+	// [1, ["dog", [2]], []]
+	inner := a.NewArray()
+	inner.Set(a.NewUint(2))
+	list := a.NewArray()
+	list.Set(a.NewString("dog"))
+	list.Set(inner)
+	v := a.NewArray()
+	v.Set(a.NewUint(1))
+	v.Set(list)
+	v.Set(a.NewArray())
+	return v
+}
+
+func TestValueWalk(t *testing.T) {
+	v := newWalkValue(&Arena{})
+
+	p := &Parser{}
+	pv, err := p.Parse(v.MarshalTo(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lv, err := p.ParseLazy(v.MarshalTo(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, v := range []*Value{v, pv, lv} {
+		var events []string
+		enter := func(path []int, v *Value) WalkAction {
+			events = append(events, fmt.Sprintf("enter %v", path))
+			return WalkContinue
+		}
+		leave := func(path []int, v *Value) WalkAction {
+			events = append(events, fmt.Sprintf("leave %v", path))
+			return WalkContinue
+		}
+		if err := v.WalkEnterLeave(enter, leave); err != nil {
+			t.Fatal(err)
+		}
+		expected := []string{
+			"enter []",
+			"enter [0]", "leave [0]",
+			"enter [1]",
+			"enter [1 0]", "leave [1 0]",
+			"enter [1 1]",
+			"enter [1 1 0]", "leave [1 1 0]",
+			"leave [1 1]",
+			"leave [1]",
+			"enter [2]", "leave [2]",
+			"leave []",
+		}
+		if !reflect.DeepEqual(events, expected) {
+			t.Fatalf("bad events %v", events)
+		}
+	}
+}
+
+func TestValueWalkSkipStop(t *testing.T) {
+	v := newWalkValue(&Arena{})
+
+	// skip the second element
+	var paths []string
+	err := v.Walk(func(path []int, v *Value) WalkAction {
+		paths = append(paths, fmt.Sprint(path))
+		if len(path) == 1 && path[0] == 1 {
+			return WalkSkip
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(paths, []string{"[]", "[0]", "[1]", "[2]"}) {
+		t.Fatalf("bad paths %v", paths)
+	}
+
+	// stop in the first bytes value
+	num := 0
+	err = v.Walk(func(path []int, v *Value) WalkAction {
+		num++
+		if v.Type() == TypeBytes {
+			return WalkStop
+		}
+		return WalkContinue
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num != 2 {
+		t.Fatalf("expected 2 values but found %d", num)
+	}
+}
+
+func TestValueFind(t *testing.T) {
+	v := newWalkValue(&Arena{})
+
+	isBytes := func(path []int, v *Value) bool {
+		return v.Type() == TypeBytes
+	}
+
+	res, err := v.Find(isBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num, err := res.GetUint64(); err != nil || num != 1 {
+		t.Fatalf("bad num %d %v", num, err)
+	}
+
+	all, err := v.FindAll(isBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("expected 3 values but found %d", len(all))
+	}
+	if !bytes.Equal(all[1].Raw(), []byte("dog")) {
+		t.Fatal("bad value")
+	}
+
+	res, err = v.Find(func(path []int, v *Value) bool {
+		return len(path) > 3
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res != nil {
+		t.Fatal("expected no value")
+	}
+}
+
+func TestValueWalkLazyError(t *testing.T) {
+	// the second element has an invalid array
+	p := &Parser{}
+	v, err := p.ParseLazy([]byte{0xC4, 0x01, 0xC2, 0x81, 0x01})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = v.Walk(func(path []int, v *Value) WalkAction {
+		return WalkContinue
+	})
+	if !errors.Is(err, ErrNonCanonicalSize) {
+		t.Fatalf("expected non canonical error but found %v", err)
+	}
+}
@@ -0,0 +1,213 @@
+package fastrlp
+
+// walkEvent is the kind of item read by the walker
+type walkEvent int
+
+const (
+	// walkBytes is a bytes value
+	walkBytes walkEvent = iota
+
+	// walkEnter is the start of an array
+	walkEnter
+
+	// walkLeave is the end of an array
+	walkLeave
+)
+
+// walkFrame is an array being walked
+type walkFrame struct {
+	// start is the position of the array
+	start uint64
+
+	// end is the position where the content of the array ends
+	end uint64
+
+	// elems is the number of elements read from the array
+	elems int
+}
+
+// walker reads the values of an encoding in order with an explicit stack.
+// It is shared by the parsers and Validate, which means that the ParserOptions
+// are enforced and the errors are built in a single place.
+type walker struct {
+	b   []byte
+	pos uint64
+
+	// base is the position of b in the input and it is added to the offsets of the errors
+	base uint64
+
+	// depth is the number of arrays that contain b
+	depth int
+
+	opts ParserOptions
+
+	// n is the number of arrays in the stack. The first arrays are stored
+	// in frames and the rest in more, so that a walker in the stack of the
+	// caller does not allocate for the usual nesting levels.
+	n      int
+	frames [16]walkFrame
+	more   []walkFrame
+}
+
+// reset starts walking the value at the start of b
+func (w *walker) reset(b []byte, base uint64, depth int, opts ParserOptions) {
+	w.b = b
+	w.pos = 0
+	w.base = base
+	w.depth = depth
+	w.opts = opts
+	w.n = 0
+}
+
+// frame returns the array at position i in the stack
+func (w *walker) frame(i int) *walkFrame {
+	if i < len(w.frames) {
+		return &w.frames[i]
+	}
+	return &w.more[i-len(w.frames)]
+}
+
+// push adds an array to the stack
+func (w *walker) push(f walkFrame) {
+	if w.n < len(w.frames) {
+		w.frames[w.n] = f
+	} else {
+		w.more = append(w.more[:w.n-len(w.frames)], f)
+	}
+	w.n++
+}
+
+// next reads the next item. For walkBytes and walkEnter it returns the position
+// of the value in b, the size of its header and the size of its content. For
+// walkLeave it returns the position of the array.
+func (w *walker) next() (walkEvent, uint64, uint64, uint64, error) {
+	return w.read(true)
+}
+
+// nextSkip is like next but it moves past arrays without reading their
+// elements. It returns walkEnter for them and there is no walkLeave.
+func (w *walker) nextSkip() (walkEvent, uint64, uint64, uint64, error) {
+	return w.read(false)
+}
+
+func (w *walker) read(enter bool) (walkEvent, uint64, uint64, uint64, error) {
+	var top *walkFrame
+	if w.n != 0 {
+		top = w.frame(w.n - 1)
+		if w.pos == top.end {
+			start := top.start
+			w.n--
+			return walkLeave, start, 0, 0, w.checkEnd(start)
+		}
+		if w.opts.MaxListElems != 0 && top.elems >= w.opts.MaxListElems {
+			return 0, 0, 0, 0, w.newLimitError(ErrMaxListElems, uint64(w.opts.MaxListElems), w.pos)
+		}
+	}
+
+	start := w.pos
+	t, hdr, size, err := readHeader(w.b[start:])
+	if err != nil {
+		return 0, 0, 0, 0, w.newError(err, start, true)
+	}
+
+	ev := walkBytes
+	if t == TypeBytes {
+		if w.opts.MaxBytesLen != 0 && size > w.opts.MaxBytesLen {
+			return 0, 0, 0, 0, w.newLimitError(ErrMaxBytesLen, w.opts.MaxBytesLen, start)
+		}
+	} else {
+		if w.opts.MaxDepth != 0 && w.depth+w.n >= w.opts.MaxDepth {
+			return 0, 0, 0, 0, w.newLimitError(ErrMaxDepth, uint64(w.opts.MaxDepth), start)
+		}
+		ev = walkEnter
+	}
+	if top != nil {
+		top.elems++
+	}
+	if ev == walkEnter && enter {
+		w.pos += hdr
+		w.push(walkFrame{start: start, end: w.pos + size})
+		return walkEnter, start, hdr, size, nil
+	}
+
+	w.pos += hdr + size
+	if top != nil && w.pos > top.end {
+		return 0, 0, 0, 0, w.newError(ErrElemTooLarge, start, false)
+	}
+	return ev, start, hdr, size, nil
+}
+
+// skipTo moves past the values of the current array, without reading the
+// elements of arrays, until the next value has index indx or the array ends.
+func (w *walker) skipTo(indx int) error {
+	top := w.frame(w.n - 1)
+	for top.elems < indx && w.pos < top.end {
+		if _, _, _, _, err := w.read(false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipRest moves past the elements left in the current array.
+// The next item is the walkLeave of the array.
+func (w *walker) skipRest() {
+	w.pos = w.frame(w.n - 1).end
+}
+
+// index returns the index that the next value has in the current array,
+// which is the number of elements of the array if next returns walkLeave
+func (w *walker) index() int {
+	return w.frame(w.n - 1).elems
+}
+
+// done returns true if the value at the start of b has been read
+func (w *walker) done() bool {
+	return w.n == 0
+}
+
+// checkEnd checks that the value at start, which ends at the current
+// position, is not larger than the array that contains it
+func (w *walker) checkEnd(start uint64) error {
+	if w.n != 0 && w.pos > w.frame(w.n-1).end {
+		return w.newError(ErrElemTooLarge, start, false)
+	}
+	return nil
+}
+
+// newError returns an error for the value at the given offset. next is set
+// if the value has not been counted yet in the array at the top of the stack.
+func (w *walker) newError(kind error, offset uint64, next bool) *ParseError {
+	return &ParseError{Offset: w.base + offset, Path: w.path(next), Kind: kind}
+}
+
+// path returns the indexes of the last value read in the arrays of the stack.
+// next is set to return the path of the value that follows it.
+func (w *walker) path(next bool) []int {
+	if w.n == 0 {
+		return nil
+	}
+	path := make([]int, w.n)
+	for i := range path {
+		path[i] = w.frame(i).elems - 1
+	}
+	if next {
+		path[w.n-1]++
+	}
+	return path
+}
+
+// withPath prepends the path of the last value read to the path of err,
+// which is relative to that value
+func (w *walker) withPath(err error) error {
+	if perr, ok := err.(*ParseError); ok && w.n != 0 {
+		perr.Path = append(w.path(false), perr.Path...)
+	}
+	return err
+}
+
+func (w *walker) newLimitError(kind error, limit uint64, offset uint64) *ParseError {
+	err := w.newError(kind, offset, true)
+	err.Err = &LimitError{Err: kind, Limit: limit}
+	return err
+}